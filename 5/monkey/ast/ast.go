@@ -38,15 +38,25 @@ func (p *Program) String() string {
 
 // let语句
 type LetStatement struct {
-	Name  *Identifier // 标识符
-	Value Expression  // 右侧表达式
+	Name    *Identifier // 标识符
+	Pattern Expression  // 解构模式（ArrayPattern或HashPattern）或quote中的unquote(name)，此时Name为nil
+	Value   Expression  // 右侧表达式
+	Const   bool        // const声明，绑定不能被重新绑定
 }
 
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("let ")
-	out.WriteString(ls.Name.String())
+	if ls.Const {
+		out.WriteString("const ")
+	} else {
+		out.WriteString("let ")
+	}
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 	out.WriteString(ls.Value.String())
 	out.WriteString(";")
@@ -113,6 +123,13 @@ type Boolean struct {
 
 func (b *Boolean) String() string { return b.Token.Literal }
 
+// null字面量
+type NullLiteral struct {
+	Token token.Token
+}
+
+func (nl *NullLiteral) String() string { return nl.Token.Literal }
+
 // 整数字面量
 type IntegerLiteral struct {
 	Token token.Token
@@ -182,19 +199,37 @@ func (ie *IfExpression) String() string {
 
 // 函数字面量
 type FunctionLiteral struct {
+	Name       *Identifier // 函数名，匿名函数为nil
 	Parameters []*Identifier
+	Defaults   []Expression // 参数默认值，与Parameters一一对应，无默认值为nil；都没有默认值时整体为nil
+	Rest       *Identifier  // 剩余参数...rest，可为nil
 	Body       *BlockStatement
+	Generator  bool // 函数体中含有yield，调用时返回生成器
+	Async      bool // async fn，调用时返回promise
 }
 
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
+	for i, p := range fl.Parameters {
+		if fl.Defaults != nil && fl.Defaults[i] != nil {
+			params = append(params, p.String()+" = "+fl.Defaults[i].String())
+		} else {
+			params = append(params, p.String())
+		}
+	}
+	if fl.Rest != nil {
+		params = append(params, "..."+fl.Rest.String())
 	}
 
+	if fl.Async {
+		out.WriteString("async ")
+	}
 	out.WriteString("fn")
+	if fl.Name != nil {
+		out.WriteString(" " + fl.Name.String())
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
@@ -205,8 +240,10 @@ func (fl *FunctionLiteral) String() string {
 
 // 调用表达式
 type CallExpression struct {
-	Function  Expression // 标识符或函数字面量
-	Arguments []Expression
+	Token            token.Token // 左括号(
+	Function         Expression  // 标识符或函数字面量
+	Arguments        []Expression
+	KeywordArguments []*KeywordArgument // 关键字参数，位于位置参数之后
 }
 
 func (ce *CallExpression) String() string {
@@ -216,6 +253,9 @@ func (ce *CallExpression) String() string {
 	for _, a := range ce.Arguments {
 		args = append(args, a.String())
 	}
+	for _, ka := range ce.KeywordArguments {
+		args = append(args, ka.String())
+	}
 
 	out.WriteString(ce.Function.String())
 	out.WriteString("(")
@@ -225,6 +265,17 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// 关键字参数
+// name: value
+type KeywordArgument struct {
+	Name  *Identifier
+	Value Expression
+}
+
+func (ka *KeywordArgument) String() string {
+	return ka.Name.String() + ": " + ka.Value.String()
+}
+
 // 字符串字面量
 type StringLiteral struct {
 	Token token.Token
@@ -254,8 +305,9 @@ func (al *ArrayLiteral) String() string {
 
 // 索引表达式
 type IndexExpression struct {
-	Left  Expression
-	Index Expression
+	Left     Expression
+	Index    Expression
+	Optional bool // a?[k]，Left为null时结果为null
 }
 
 func (ie *IndexExpression) String() string {
@@ -263,6 +315,9 @@ func (ie *IndexExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString("[")
 	out.WriteString(ie.Index.String())
 	out.WriteString("])")
@@ -270,6 +325,30 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+// 成员访问表达式
+// object.property、object?.property
+type MemberExpression struct {
+	Token    token.Token // 点号.或?.
+	Object   Expression
+	Property *Identifier
+	Optional bool // object?.property，Object为null时结果为null
+}
+
+func (me *MemberExpression) String() string {
+	return "(" + me.Object.String() + me.Token.Literal + me.Property.String() + ")"
+}
+
+// 可选链
+// 链中的?.或?[遇到null时，整条链其后的成员访问、索引和调用都被跳过，结果为null
+// a?.b.c、a?.m()；括号结束一条链，(a?.b).c中的.c不会被跳过
+type OptionalChain struct {
+	Expression Expression // 包含可选访问的成员访问、索引或调用表达式
+}
+
+func (oc *OptionalChain) String() string {
+	return oc.Expression.String()
+}
+
 // 哈希字面量
 type HashLiteral struct {
 	Pairs map[Expression]Expression
@@ -312,3 +391,380 @@ func (ml *MacroLiteral) String() string {
 
 	return out.String()
 }
+
+// throw语句
+type ThrowStatement struct {
+	Token token.Token // throw
+	Value Expression  // 抛出的值
+}
+
+func (ts *ThrowStatement) String() string {
+	return "throw " + ts.Value.String() + ";" + "\n"
+}
+
+// yield语句，只能出现在函数体中
+type YieldStatement struct {
+	Token token.Token // yield
+	Value Expression
+}
+
+func (ys *YieldStatement) String() string {
+	return "yield " + ys.Value.String() + ";" + "\n"
+}
+
+// await表达式
+// await promise
+type AwaitExpression struct {
+	Token token.Token // await
+	Value Expression
+}
+
+func (ae *AwaitExpression) String() string {
+	return "(await " + ae.Value.String() + ")"
+}
+
+// try表达式
+// try { } catch (e) { } finally { }，catch和finally至少有一个
+type TryExpression struct {
+	Block      *BlockStatement
+	CatchParam *Identifier // 可为nil
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+
+	if te.Catch != nil {
+		out.WriteString(" catch")
+		if te.CatchParam != nil {
+			out.WriteString("(" + te.CatchParam.String() + ")")
+		}
+		out.WriteString(" ")
+		out.WriteString(te.Catch.String())
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
+
+// for-in表达式
+// for (x in collection) { ... }、for (k, v in hash) { ... }
+type ForExpression struct {
+	Key      *Identifier // 单变量形式为nil
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fe *ForExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fe.Key != nil {
+		out.WriteString(fe.Key.String() + ", ")
+	}
+	out.WriteString(fe.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fe.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fe.Body.String())
+
+	return out.String()
+}
+
+// select表达式
+// select { case let v = recv(ch): ... case send(ch, x): ... default: ... }
+type SelectExpression struct {
+	Cases   []*SelectCase
+	Default *BlockStatement // 可为nil
+}
+
+func (se *SelectExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("select { ")
+	for _, c := range se.Cases {
+		out.WriteString(c.String())
+		out.WriteString(" ")
+	}
+	if se.Default != nil {
+		out.WriteString("default: ")
+		out.WriteString(se.Default.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// select的分支，Call为recv(ch)或send(ch, value)
+type SelectCase struct {
+	Name *Identifier // case let v = recv(ch)中接收值绑定的名称，可为nil
+	Call *CallExpression
+	Body *BlockStatement
+}
+
+func (sc *SelectCase) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("case ")
+	if sc.Name != nil {
+		out.WriteString("let " + sc.Name.String() + " = ")
+	}
+	out.WriteString(sc.Call.String())
+	out.WriteString(": ")
+	out.WriteString(sc.Body.String())
+
+	return out.String()
+}
+
+// switch表达式
+// switch (x) { case 1, 2: ... default: ... }
+type SwitchExpression struct {
+	Subject Expression
+	Cases   []*SwitchCase
+	Default *BlockStatement // 可为nil
+}
+
+func (se *SwitchExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("switch (")
+	out.WriteString(se.Subject.String())
+	out.WriteString(") { ")
+	for _, c := range se.Cases {
+		out.WriteString(c.String())
+		out.WriteString(" ")
+	}
+	if se.Default != nil {
+		out.WriteString("default: ")
+		out.WriteString(se.Default.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// switch的分支，Values中任一值与Subject相等即执行Body
+type SwitchCase struct {
+	Values []Expression
+	Body   *BlockStatement
+}
+
+func (sc *SwitchCase) String() string {
+	var out bytes.Buffer
+
+	values := []string{}
+	for _, v := range sc.Values {
+		values = append(values, v.String())
+	}
+
+	out.WriteString("case ")
+	out.WriteString(strings.Join(values, ", "))
+	out.WriteString(": ")
+	out.WriteString(sc.Body.String())
+
+	return out.String()
+}
+
+// match表达式
+// match value { pattern => expr, pattern if guard => expr }
+type MatchExpression struct {
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, a := range me.Arms {
+		arms = append(arms, a.String())
+	}
+
+	out.WriteString("match ")
+	out.WriteString(me.Subject.String())
+	out.WriteString(" {")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// match分支
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression // 可为nil
+	Body    Expression
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+// 数组模式
+// [a, b, ...rest]
+type ArrayPattern struct {
+	Elements []Expression
+	Rest     *Identifier // 可为nil，表示元素个数必须一致
+}
+
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// 哈希模式
+// {"key": pattern, name}，name是"name": name的简写
+type HashPattern struct {
+	Keys   []Expression
+	Values []Expression
+}
+
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		pairs = append(pairs, key.String()+" : "+hp.Values[i].String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// 结构体声明
+// struct Point { x, y, fn norm(self) { ... } }
+type StructStatement struct {
+	Token   token.Token // struct
+	Name    *Identifier
+	Fields  []*Identifier
+	Methods []*FunctionLiteral // 第一个参数为实例self
+}
+
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	members := []string{}
+	for _, f := range ss.Fields {
+		members = append(members, f.String())
+	}
+	for _, m := range ss.Methods {
+		members = append(members, m.String())
+	}
+
+	out.WriteString("struct ")
+	out.WriteString(ss.Name.String())
+	out.WriteString(" {")
+	out.WriteString(strings.Join(members, ", "))
+	out.WriteString("}")
+	out.WriteString("\n")
+
+	return out.String()
+}
+
+// 枚举声明
+// enum Shape { Circle(r), Rect(w, h), Empty }
+type EnumStatement struct {
+	Token    token.Token // enum
+	Name     *Identifier
+	Variants []*EnumVariant
+}
+
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, v := range es.Variants {
+		variants = append(variants, v.String())
+	}
+
+	out.WriteString("enum ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" {")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString("}")
+	out.WriteString("\n")
+
+	return out.String()
+}
+
+// 枚举变体，Fields为nil时是无字段的变体
+type EnumVariant struct {
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ev *EnumVariant) String() string {
+	if ev.Fields == nil {
+		return ev.Name.String()
+	}
+
+	fields := []string{}
+	for _, f := range ev.Fields {
+		fields = append(fields, f.String())
+	}
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// 枚举变体模式
+// Shape.Circle(r)、Color.Red
+type VariantPattern struct {
+	Enum    *Identifier
+	Variant *Identifier
+	Fields  []Expression // 各字段的模式，无字段的变体为nil
+}
+
+func (vp *VariantPattern) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(vp.Enum.String())
+	out.WriteString(".")
+	out.WriteString(vp.Variant.String())
+	if vp.Fields != nil {
+		fields := []string{}
+		for _, f := range vp.Fields {
+			fields = append(fields, f.String())
+		}
+		out.WriteString("(")
+		out.WriteString(strings.Join(fields, ", "))
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
@@ -0,0 +1,63 @@
+package ast
+
+import "reflect"
+
+// 深拷贝节点，返回的节点与原节点不共享任何子节点
+// 原节点中被多处引用的同一子节点（如函数声明的名称），拷贝后仍为同一节点
+func Copy(node Node) Node {
+	if node == nil {
+		return nil
+	}
+	copied := copyValue(reflect.ValueOf(node), map[uintptr]reflect.Value{})
+	return copied.Interface().(Node)
+}
+
+func copyValue(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := seen[v.Pointer()]; ok {
+			return c
+		}
+		c := reflect.New(v.Elem().Type())
+		seen[v.Pointer()] = c
+		c.Elem().Set(copyValue(v.Elem(), seen))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem(), seen))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			c.Field(i).Set(copyValue(v.Field(i), seen))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i), seen))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(copyValue(iter.Key(), seen), copyValue(iter.Value(), seen))
+		}
+		return c
+	default:
+		return v
+	}
+}
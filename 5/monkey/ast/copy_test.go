@@ -0,0 +1,56 @@
+package ast
+
+import (
+	"monkey/token"
+	"reflect"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	ident := func(name string) *Identifier {
+		return &Identifier{Token: token.Token{Type: token.IDENT, Literal: name}}
+	}
+	one := &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1"}, Value: 1}
+
+	name := ident("f")
+	original := &Program{Statements: []Statement{
+		&LetStatement{Name: name, Value: &FunctionLiteral{
+			Name:       name,
+			Parameters: []*Identifier{ident("x")},
+			Body: &BlockStatement{Statements: []Statement{
+				&ExpressionStatement{Expression: &InfixExpression{Left: ident("x"), Token: token.Token{Type: token.PLUS, Literal: "+"}, Right: one}},
+			}},
+		}},
+		&ExpressionStatement{Expression: &HashLiteral{Pairs: map[Expression]Expression{one: ident("x")}}},
+	}}
+
+	copied := Copy(original).(*Program)
+	if !reflect.DeepEqual(original.Statements[0], copied.Statements[0]) ||
+		original.String() != copied.String() {
+		t.Fatalf("copy differs from original. want=%q, got=%q", original.String(), copied.String())
+	}
+
+	let := copied.Statements[0].(*LetStatement)
+	fn := let.Value.(*FunctionLiteral)
+	if let.Name == name {
+		t.Errorf("copy shares identifier with original")
+	}
+	if fn.Name != let.Name {
+		t.Errorf("copy does not preserve shared identifier")
+	}
+
+	hash := copied.Statements[1].(*ExpressionStatement).Expression.(*HashLiteral)
+	if _, ok := hash.Pairs[one]; ok {
+		t.Errorf("copy shares hash key with original")
+	}
+
+	infix := fn.Body.Statements[0].(*ExpressionStatement).Expression.(*InfixExpression)
+	infix.Right = ident("y")
+	if original.String() == copied.String() {
+		t.Errorf("modifying copy changed original. got=%q", original.String())
+	}
+
+	if Copy(nil) != nil {
+		t.Errorf("Copy(nil) was not nil")
+	}
+}
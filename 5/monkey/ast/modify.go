@@ -20,6 +20,10 @@ func Modify(node Node, modifier ModifierFunc) Node {
 	case *IndexExpression:
 		node.Left, _ = Modify(node.Left, modifier).(Expression)
 		node.Index, _ = Modify(node.Index, modifier).(Expression)
+	case *MemberExpression:
+		node.Object, _ = Modify(node.Object, modifier).(Expression)
+	case *OptionalChain:
+		node.Expression, _ = Modify(node.Expression, modifier).(Expression)
 	case *IfExpression:
 		node.Condition, _ = Modify(node.Condition, modifier).(Expression)
 		node.Consequence, _ = Modify(node.Consequence, modifier).(*BlockStatement)
@@ -33,16 +37,98 @@ func Modify(node Node, modifier ModifierFunc) Node {
 	case *ReturnStatement:
 		node.ReturnValue, _ = Modify(node.ReturnValue, modifier).(Expression)
 	case *LetStatement:
+		if node.Pattern != nil {
+			node.Pattern, _ = Modify(node.Pattern, modifier).(Expression)
+		}
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+	case *StructStatement:
+		for i := range node.Methods {
+			node.Methods[i], _ = Modify(node.Methods[i], modifier).(*FunctionLiteral)
+		}
+	case *ThrowStatement:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+	case *YieldStatement:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+	case *AwaitExpression:
 		node.Value, _ = Modify(node.Value, modifier).(Expression)
+	case *TryExpression:
+		node.Block, _ = Modify(node.Block, modifier).(*BlockStatement)
+		if node.Catch != nil {
+			node.Catch, _ = Modify(node.Catch, modifier).(*BlockStatement)
+		}
+		if node.Finally != nil {
+			node.Finally, _ = Modify(node.Finally, modifier).(*BlockStatement)
+		}
+	case *SelectExpression:
+		for _, c := range node.Cases {
+			c.Call, _ = Modify(c.Call, modifier).(*CallExpression)
+			c.Body, _ = Modify(c.Body, modifier).(*BlockStatement)
+		}
+		if node.Default != nil {
+			node.Default, _ = Modify(node.Default, modifier).(*BlockStatement)
+		}
+	case *SwitchExpression:
+		node.Subject, _ = Modify(node.Subject, modifier).(Expression)
+		for _, c := range node.Cases {
+			for i := range c.Values {
+				c.Values[i], _ = Modify(c.Values[i], modifier).(Expression)
+			}
+			c.Body, _ = Modify(c.Body, modifier).(*BlockStatement)
+		}
+		if node.Default != nil {
+			node.Default, _ = Modify(node.Default, modifier).(*BlockStatement)
+		}
+	case *ForExpression:
+		if node.Key != nil {
+			node.Key, _ = Modify(node.Key, modifier).(*Identifier)
+		}
+		node.Value, _ = Modify(node.Value, modifier).(*Identifier)
+		node.Iterable, _ = Modify(node.Iterable, modifier).(Expression)
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
 	case *FunctionLiteral:
 		for i := range node.Parameters {
 			node.Parameters[i], _ = Modify(node.Parameters[i], modifier).(*Identifier)
 		}
+		for i := range node.Defaults {
+			if node.Defaults[i] != nil {
+				node.Defaults[i], _ = Modify(node.Defaults[i], modifier).(Expression)
+			}
+		}
 		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+	case *CallExpression:
+		node.Function, _ = Modify(node.Function, modifier).(Expression)
+		for i := range node.Arguments {
+			node.Arguments[i], _ = Modify(node.Arguments[i], modifier).(Expression)
+		}
+		for _, ka := range node.KeywordArguments {
+			ka.Value, _ = Modify(ka.Value, modifier).(Expression)
+		}
 	case *ArrayLiteral:
 		for i := range node.Elements {
 			node.Elements[i], _ = Modify(node.Elements[i], modifier).(Expression)
 		}
+	case *MatchExpression:
+		node.Subject, _ = Modify(node.Subject, modifier).(Expression)
+		for _, arm := range node.Arms {
+			arm.Pattern, _ = Modify(arm.Pattern, modifier).(Expression)
+			if arm.Guard != nil {
+				arm.Guard, _ = Modify(arm.Guard, modifier).(Expression)
+			}
+			arm.Body, _ = Modify(arm.Body, modifier).(Expression)
+		}
+	case *ArrayPattern:
+		for i := range node.Elements {
+			node.Elements[i], _ = Modify(node.Elements[i], modifier).(Expression)
+		}
+	case *VariantPattern:
+		for i := range node.Fields {
+			node.Fields[i], _ = Modify(node.Fields[i], modifier).(Expression)
+		}
+	case *HashPattern:
+		for i := range node.Keys {
+			node.Keys[i], _ = Modify(node.Keys[i], modifier).(Expression)
+			node.Values[i], _ = Modify(node.Values[i], modifier).(Expression)
+		}
 	case *HashLiteral:
 		newPairs := make(map[Expression]Expression)
 		for key, val := range node.Pairs {
@@ -114,10 +114,156 @@ func TestModify(t *testing.T) {
 				},
 			},
 		},
+		{
+			&FunctionLiteral{
+				Parameters: []*Identifier{{}},
+				Defaults:   []Expression{one()},
+				Body:       &BlockStatement{Statements: []Statement{}},
+			},
+			&FunctionLiteral{
+				Parameters: []*Identifier{{}},
+				Defaults:   []Expression{two()},
+				Body:       &BlockStatement{Statements: []Statement{}},
+			},
+		},
 		{
 			&ArrayLiteral{Elements: []Expression{one(), one()}},
 			&ArrayLiteral{Elements: []Expression{two(), two()}},
 		},
+		{
+			&MatchExpression{
+				Subject: one(),
+				Arms: []*MatchArm{
+					{
+						Pattern: &ArrayPattern{Elements: []Expression{one()}},
+						Guard:   one(),
+						Body:    one(),
+					},
+					{
+						Pattern: &HashPattern{Keys: []Expression{one()}, Values: []Expression{one()}},
+						Body:    one(),
+					},
+				},
+			},
+			&MatchExpression{
+				Subject: two(),
+				Arms: []*MatchArm{
+					{
+						Pattern: &ArrayPattern{Elements: []Expression{two()}},
+						Guard:   two(),
+						Body:    two(),
+					},
+					{
+						Pattern: &HashPattern{Keys: []Expression{two()}, Values: []Expression{two()}},
+						Body:    two(),
+					},
+				},
+			},
+		},
+		{
+			&CallExpression{
+				Function:         &Identifier{Token: token.Token{Type: token.IDENT, Literal: "f"}},
+				Arguments:        []Expression{one()},
+				KeywordArguments: []*KeywordArgument{{Name: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "a"}}, Value: one()}},
+			},
+			&CallExpression{
+				Function:         &Identifier{Token: token.Token{Type: token.IDENT, Literal: "f"}},
+				Arguments:        []Expression{two()},
+				KeywordArguments: []*KeywordArgument{{Name: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "a"}}, Value: two()}},
+			},
+		},
+		{
+			&ForExpression{
+				Value:    &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}},
+				Iterable: one(),
+				Body:     &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&ForExpression{
+				Value:    &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}},
+				Iterable: two(),
+				Body:     &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+		{
+			&ThrowStatement{Value: one()},
+			&ThrowStatement{Value: two()},
+		},
+		{
+			&YieldStatement{Value: one()},
+			&YieldStatement{Value: two()},
+		},
+		{
+			&AwaitExpression{Value: one()},
+			&AwaitExpression{Value: two()},
+		},
+		{
+			&MemberExpression{Object: one(), Property: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}}},
+			&MemberExpression{Object: two(), Property: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}}},
+		},
+		{
+			&OptionalChain{Expression: &IndexExpression{Left: one(), Index: one(), Optional: true}},
+			&OptionalChain{Expression: &IndexExpression{Left: two(), Index: two(), Optional: true}},
+		},
+		{
+			&VariantPattern{Fields: []Expression{one()}},
+			&VariantPattern{Fields: []Expression{two()}},
+		},
+		{
+			&StructStatement{Methods: []*FunctionLiteral{{
+				Parameters: []*Identifier{},
+				Body:       &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			}}},
+			&StructStatement{Methods: []*FunctionLiteral{{
+				Parameters: []*Identifier{},
+				Body:       &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			}}},
+		},
+		{
+			&SelectExpression{
+				Cases: []*SelectCase{{
+					Call: &CallExpression{Function: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "recv"}}, Arguments: []Expression{one()}},
+					Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				}},
+				Default: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&SelectExpression{
+				Cases: []*SelectCase{{
+					Call: &CallExpression{Function: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "recv"}}, Arguments: []Expression{two()}},
+					Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+				}},
+				Default: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+		{
+			&SwitchExpression{
+				Subject: one(),
+				Cases: []*SwitchCase{{
+					Values: []Expression{one(), one()},
+					Body:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				}},
+				Default: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&SwitchExpression{
+				Subject: two(),
+				Cases: []*SwitchCase{{
+					Values: []Expression{two(), two()},
+					Body:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+				}},
+				Default: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+		{
+			&TryExpression{
+				Block:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				Catch:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&TryExpression{
+				Block:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+				Catch:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
 		/*
 			{
 				&HashLiteral{
@@ -115,4 +115,41 @@ var builtins = map[string]*object.Builtin{
 			return &object.Array{Elements: newElements}
 		},
 	},
+	"json_parse":      &object.Builtin{Fn: jsonParse},
+	"json_stringify":  &object.Builtin{Fn: jsonStringify, Keywords: []string{"indent"}},
+	"read_file":       &object.Builtin{Fn: readFile},
+	"write_file":      &object.Builtin{Fn: writeFile},
+	"list_dir":        &object.Builtin{Fn: listDir},
+	"exists":          &object.Builtin{Fn: fileExists},
+	"regex":           &object.Builtin{Fn: regexCompile},
+	"match":           &object.Builtin{Fn: regexMatch},
+	"find_all":        &object.Builtin{Fn: regexFindAll},
+	"replace_regex":   &object.Builtin{Fn: regexReplace},
+	"split_regex":     &object.Builtin{Fn: regexSplit},
+	"now":             &object.Builtin{Fn: timeNow},
+	"format_time":     &object.Builtin{Fn: timeFormat},
+	"parse_time":      &object.Builtin{Fn: timeParse},
+	"duration":        &object.Builtin{Fn: timeDuration},
+	"format_duration": &object.Builtin{Fn: timeFormatDuration},
+	"sleep":           &object.Builtin{Fn: timeSleep},
+	"abs":             &object.Builtin{Fn: mathAbs},
+	"min":             &object.Builtin{Fn: mathMin},
+	"max":             &object.Builtin{Fn: mathMax},
+	"pow":             &object.Builtin{Fn: mathPow},
+	"sqrt":            &object.Builtin{Fn: mathSqrt},
+	"seed":            &object.Builtin{Fn: mathSeed},
+	"random":          &object.Builtin{Fn: mathRandom},
+	"random_int":      &object.Builtin{Fn: mathRandomInt},
+	"shuffle":         &object.Builtin{Fn: mathShuffle},
+	"range":           &object.Builtin{Fn: rangeBuiltin},
+	"next":            &object.Builtin{Fn: generatorNext},
+	"close":           &object.Builtin{Fn: closeBuiltin},
+	"join":            &object.Builtin{Fn: join},
+	"channel":         &object.Builtin{Fn: channel},
+	"send":            &object.Builtin{Fn: send},
+	"recv":            &object.Builtin{Fn: recv},
+	"set_timeout":     &object.Builtin{Fn: setTimeout},
+	"clear_timeout":   &object.Builtin{Fn: clearTimeout},
+	"split":           &object.Builtin{Fn: stringSplit},
+	"gensym":          &object.Builtin{Fn: gensym},
 }
@@ -0,0 +1,241 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+	"reflect"
+	"runtime"
+)
+
+// 任务之间的隔离规则：
+// spawn的函数与调用方共享其闭包环境，环境本身的读写是并发安全的；
+// 数组、哈希等值不会被内置函数原地修改，可以直接通过通道传递
+
+func init() {
+	builtins["spawn"] = &object.Builtin{Fn: spawn}
+}
+
+// spawn(fn, args...)
+// 在新的goroutine中调用fn，返回任务对象，用join取得返回值
+func spawn(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newError("wrong number of arguments. got=%d, want=1+",
+			len(args))
+	}
+	switch args[0].(type) {
+	case *object.Function, *object.Builtin:
+	default:
+		return newError("argument to `spawn` must be FUNCTION, got %s",
+			args[0].Type())
+	}
+
+	fn, fnArgs := args[0], args[1:]
+	task := &object.Task{Done: make(chan struct{})}
+	go func() {
+		defer close(task.Done)
+		task.Result = applyFunction(fn, fnArgs)
+	}()
+
+	return task
+}
+
+// join(task)
+// 等待任务结束，返回其结果；任务中的错误在这里返回
+func join(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	task, ok := args[0].(*object.Task)
+	if !ok {
+		return newError("argument to `join` must be TASK, got %s",
+			args[0].Type())
+	}
+
+	<-task.Done
+
+	// 多个调用方可能同时join同一个任务，各自向错误追加调用栈和位置，返回副本避免共享
+	if err, ok := task.Result.(*object.Error); ok {
+		copied := *err
+		copied.Stack = append([]string(nil), err.Stack...)
+		return &copied
+	}
+	return task.Result
+}
+
+// channel() 或 channel(size)
+// 创建通道，size为缓冲区大小，默认无缓冲
+func channel(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("wrong number of arguments. got=%d, want=0 or 1",
+			len(args))
+	}
+	values, errObj := integerArgs("channel", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	size := 0
+	if len(values) == 1 {
+		if values[0] < 0 {
+			return newError("negative size to `channel`: %d", values[0])
+		}
+		size = int(values[0])
+	}
+
+	return object.NewChannel(size)
+}
+
+func channelArg(name string, args []object.Object, want int) (*object.Channel, *object.Error) {
+	if len(args) != want {
+		return nil, newError("wrong number of arguments. got=%d, want=%d",
+			len(args), want)
+	}
+	ch, ok := args[0].(*object.Channel)
+	if !ok {
+		return nil, newError("argument to `%s` must be CHANNEL, got %s",
+			name, args[0].Type())
+	}
+	return ch, nil
+}
+
+// send(ch, value)
+// 发送值，通道已满时阻塞
+func send(args ...object.Object) (result object.Object) {
+	ch, errObj := channelArg("send", args, 2)
+	if errObj != nil {
+		return errObj
+	}
+
+	defer recoverClosedSend(&result)
+
+	ch.Value <- args[1]
+	return NULL
+}
+
+// 向已关闭的通道发送时Go会panic，将其转换为错误；其他panic继续向上抛出
+func recoverClosedSend(result *object.Object) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if err, ok := r.(runtime.Error); ok && err.Error() == "send on closed channel" {
+		*result = newError("send on closed channel")
+		return
+	}
+	panic(r)
+}
+
+// recv(ch)
+// 接收值，没有值时阻塞；通道关闭且已取空时返回null
+func recv(args ...object.Object) object.Object {
+	ch, errObj := channelArg("recv", args, 1)
+	if errObj != nil {
+		return errObj
+	}
+
+	value, ok := <-ch.Value
+	if !ok {
+		return NULL
+	}
+	return value
+}
+
+// close(ch) 或 close(generator)
+// 关闭通道，或提前结束生成器
+func closeBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Channel:
+		if !arg.Close() {
+			return newError("close of closed channel")
+		}
+	case *object.Generator:
+		arg.Stop()
+	default:
+		return newError("argument to `close` must be CHANNEL or GENERATOR, got %s",
+			args[0].Type())
+	}
+
+	return NULL
+}
+
+// 求值select表达式
+// 同时等待各分支的recv/send，执行最先就绪的分支；有default时不等待
+func evalSelectExpression(se *ast.SelectExpression, env *object.Environment) object.Object {
+	cases := make([]reflect.SelectCase, 0, len(se.Cases)+1)
+
+	for _, c := range se.Cases {
+		chObj := Eval(c.Call.Arguments[0], env)
+		if isError(chObj) {
+			return chObj
+		}
+		ch, ok := chObj.(*object.Channel)
+		if !ok {
+			return newErrorOfKind(TYPE_ERROR, "argument to `%s` must be CHANNEL, got %s",
+				c.Call.Function.String(), chObj.Type())
+		}
+
+		if c.Call.Function.String() == "recv" {
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(ch.Value),
+			})
+			continue
+		}
+
+		value := Eval(c.Call.Arguments[1], env)
+		if isError(value) {
+			return value
+		}
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectSend,
+			Chan: reflect.ValueOf(ch.Value),
+			Send: reflect.ValueOf(&value).Elem(),
+		})
+	}
+
+	if se.Default != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+
+	chosen, received, ok, errObj := selectChannels(cases)
+	if errObj != nil {
+		return errObj
+	}
+
+	if chosen == len(se.Cases) {
+		return evalCaseBody(se.Default, blockEnvironment(se.Default, env))
+	}
+
+	c := se.Cases[chosen]
+	caseEnv := object.NewEnclosedEnvironment(env)
+	if c.Name != nil {
+		value := object.Object(NULL)
+		if ok {
+			value = received.Interface().(object.Object)
+		}
+		caseEnv.Set(c.Name.Token.Literal, value)
+	}
+
+	return evalCaseBody(c.Body, caseEnv)
+}
+
+// 等待select的分支就绪，只在这里处理向已关闭通道发送的panic，分支体中的panic不受影响
+func selectChannels(cases []reflect.SelectCase) (chosen int, received reflect.Value, ok bool, err object.Object) {
+	defer recoverClosedSend(&err)
+	chosen, received, ok = reflect.Select(cases)
+	return chosen, received, ok, nil
+}
+
+func evalCaseBody(body *ast.BlockStatement, env *object.Environment) object.Object {
+	result := evalBlockStatement(body, env)
+	if result == nil {
+		return NULL
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package evaluator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConcurrency(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let t = spawn(fn(a, b) { a + b }, 1, 2); join(t)", "3"},
+		{"join(spawn(len, [1, 2]))", "2"},
+		{`let ch = channel();
+		  let worker = fn(n) { send(ch, n * n) };
+		  spawn(worker, 2);
+		  spawn(worker, 3);
+		  let a = recv(ch);
+		  let b = recv(ch);
+		  a + b`, "13"},
+		{`let ch = channel();
+		  let tasks = for (i in range(20)) { spawn(fn() { send(ch, i * 2) }) };
+		  max(for (i in range(20)) { recv(ch) })`, "38"},
+		{"let ch = channel(3); send(ch, 1); send(ch, 2); close(ch); for (x in ch) { x }", "[1, 2]"},
+		{"let ch = channel(1); close(ch); recv(ch)", "null"},
+		{"channel(2)", "<channel 0/2>"},
+		{"let ch = channel(1); send(ch, 5); select { case let v = recv(ch): v * 2 default: 0 }", "10"},
+		{`let ch = channel(); select { case recv(ch): "got" default: "idle" }`, "idle"},
+		{`let ch = channel(1); select { case send(ch, 1): "sent" }`, "sent"},
+		{"let ch = channel(); spawn(fn() { send(ch, 7) }); select { case let v = recv(ch): v }", "7"},
+		{"let ch = channel(); close(ch); select { case let v = recv(ch): v }", "null"},
+		{`let a = channel(); let b = channel(1); send(b, "b");
+		  select { case let v = recv(a): v case let v = recv(b): v + "!" }`, "b!"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestConcurrencyErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"join(spawn(fn() { missing }))", "identifier not found: missing"},
+		{"spawn(1)", "argument to `spawn` must be FUNCTION, got INTEGER"},
+		{"join(1)", "argument to `join` must be TASK, got INTEGER"},
+		{"channel(-1)", "negative size to `channel`: -1"},
+		{"let ch = channel(1); close(ch); send(ch, 1)", "send on closed channel"},
+		{"let ch = channel(1); close(ch); close(ch)", "close of closed channel"},
+		{"recv([])", "argument to `recv` must be CHANNEL, got ARRAY"},
+		{"let ch = channel(1); close(ch); select { case send(ch, 1): 1 }", "send on closed channel"},
+		{"select { case recv(1): 1 }", "argument to `recv` must be CHANNEL, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestJoinFromSeveralTasks(t *testing.T) {
+	// 多个任务同时join同一个出错的任务，各自追加调用栈，需要在-race下运行
+	input := `let t = spawn(fn() { sleep(20); missing });
+	let g = fn() { join(t) };
+	let a = spawn(fn() { g() });
+	let b = spawn(fn() { g() });
+	let failed = fn(task) { try { join(task); 0 } catch { 1 } };
+	failed(a) + failed(b) + failed(t)`
+
+	testIntegerObject(t, testEval(input), 3)
+}
+
+func TestSelectDoesNotHidePanics(t *testing.T) {
+	tests := []string{
+		"select { default: [1][0] / 0 }",
+		"let ch = channel(1); send(ch, 1); select { case let v = recv(ch): v / 0 }",
+	}
+
+	for _, input := range tests {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil || fmt.Sprint(r) != "runtime error: integer divide by zero" {
+					t.Errorf("wrong panic for %q. got=%v", input, r)
+				}
+			}()
+			evaluated := testEval(input)
+			t.Errorf("expected panic for %q. got=%s", input, evaluated.Inspect())
+		}()
+	}
+}
@@ -0,0 +1,38 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 求值枚举声明，在当前环境中绑定枚举类型
+// 通过成员访问取出变体：有字段的变体可以调用构造枚举值，无字段的变体本身就是值
+func evalEnumStatement(es *ast.EnumStatement, env *object.Environment) object.Object {
+	enum := object.NewEnum(es.Name.Token.Literal)
+
+	for _, v := range es.Variants {
+		variant := &object.Variant{Enum: enum, Name: v.Name.Token.Literal}
+		for _, f := range v.Fields {
+			variant.Fields = append(variant.Fields, f.Token.Literal)
+		}
+		if len(variant.Fields) == 0 {
+			variant.Unit = &object.EnumValue{Variant: variant}
+		}
+		enum.Variants = append(enum.Variants, variant)
+	}
+
+	if err := bind(env, enum.Name, enum, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 调用变体构造枚举值，实参按字段顺序绑定，也可以用字段名作为关键字参数
+func newEnumValue(variant *object.Variant, args []object.Object, keywords []keywordArgument) object.Object {
+	values, err := constructorArguments(variant.FullName(), variant.Fields, args, keywords)
+	if err != nil {
+		return err
+	}
+
+	return &object.EnumValue{Variant: variant, Values: values}
+}
@@ -0,0 +1,84 @@
+package evaluator
+
+import "testing"
+
+const shapeEnum = `
+enum Shape { Circle(r), Rect(w, h), Empty }
+let area = fn(s) {
+	match s {
+		Shape.Circle(r) => 3 * r * r,
+		Shape.Rect(w, h) if w == h => w * w,
+		Shape.Rect(w, h) => w * h,
+		Shape.Empty => 0,
+	}
+};
+`
+
+func TestEnums(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Shape.Circle(1)", "Shape.Circle(r: 1)"},
+		{"Shape.Rect(h: 3, w: 2)", "Shape.Rect(w: 2, h: 3)"},
+		{"Shape.Empty", "Shape.Empty"},
+		{"Shape.Circle", "<variant Shape.Circle>"},
+		{"Shape", "<enum Shape>"},
+		{"Shape.Rect(2, 3).h", "3"},
+		{"area(Shape.Circle(2))", "12"},
+		{"area(Shape.Rect(3, 3))", "9"},
+		{"area(Shape.Rect(2, 3))", "6"},
+		{"area(Shape.Empty)", "0"},
+		{"Shape.Circle(1) == Shape.Circle(1)", "true"},
+		{"Shape.Circle(1) == Shape.Circle(2)", "false"},
+		{"Shape.Circle(1) != Shape.Rect(1, 1)", "true"},
+		{"Shape.Empty == Shape.Empty", "true"},
+		{"Shape.Circle([1, 2]) == Shape.Circle([1, 2])", "true"},
+		{"Shape.Empty == 1", "false"},
+		{"{Shape.Circle(1): 1, Shape.Empty: 2}[Shape.Circle(1)]", "1"},
+		{"{Shape.Circle(1): 1}[Shape.Circle(2)]", "null"},
+		{"{Shape.Empty: 2}[Shape.Empty]", "2"},
+		{"match Shape.Circle(Shape.Empty) { Shape.Circle(Shape.Empty) => 1, _ => 2 }", "1"},
+		{"match Shape.Circle(5) { Shape.Circle(1) => 1, Shape.Circle(x) => x }", "5"},
+		{"match 1 { Shape.Empty => 1, _ => 2 }", "2"},
+		{"match Shape.Rect(1, 2) { Shape.Rect => 1, _ => 2 }", "1"},
+		{"let old = Shape.Circle(1); enum Shape { Circle(r) }; match old { Shape.Circle(r) => r, _ => 0 }", "0"},
+		{"let f = fn(s) { enum Shape { Circle(r) }; match s { Shape.Circle(r) => r, _ => 0 } }; f(Shape.Circle(1))", "0"},
+		{"let old = Shape.Empty; enum Shape { Empty }; [old == Shape.Empty, {old: 1, Shape.Empty: 2}[old]]", "[false, 1]"},
+		{"let old = Shape.Circle(1); enum Shape { Circle(r) }; {old: 1}[Shape.Circle(1)]", "null"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(shapeEnum + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestEnumErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Shape.Square(1)", "`Shape` has no variant `Square`"},
+		{"Shape.Rect(1)", "wrong number of arguments to `Shape.Rect`. got=1, want=2"},
+		{"Shape.Rect(1, d: 2)", "unknown keyword argument `d`"},
+		{"Shape.Circle(1).w", "`Shape.Circle` has no field `w`"},
+		{"Shape.Empty()", "not a function: ENUM_VALUE"},
+		{"{Shape.Circle([1]): 1}", "unusable as hash key: ENUM_VALUE"},
+		{"Shape.Circle(1) + 1", "type mismatch: ENUM_VALUE + INTEGER"},
+		{"area(1)", "no pattern matched value: 1"},
+		{"let [x] = [Shape.Empty]; match x { Shape.Circle(r) => r }", "no pattern matched value: Shape.Empty"},
+		{`match Shape.Circle(1) { Shape.Cirle(r) => r, _ => "fell through" }`, "`Shape` has no variant `Cirle`"},
+		{`match Shape.Circle(1) { Shap.Circle(r) => r, _ => "fell through" }`, "unknown enum `Shap` in pattern Shap.Circle(r)"},
+		{`match 1 { area.Circle(r) => r, _ => 2 }`, "`area` in pattern area.Circle(r) is not an enum, got FUNCTION"},
+		{`match Shape.Circle(1) { Shape.Circle(a, b) => a, _ => 2 }`, "wrong number of fields in pattern Shape.Circle(a, b). got=2, want=1"},
+		{`match Shape.Empty { Shape.Empty(x) => x, _ => 2 }`, "wrong number of fields in pattern Shape.Empty(x). got=1, want=0"},
+		{`match [Shape.Empty] { [Shape.Nope] => 1, _ => 2 }`, "`Shape` has no variant `Nope`"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(shapeEnum+tt.input), tt.expected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"monkey/ast"
 	"monkey/object"
+	"monkey/token"
 )
 
 var (
@@ -12,6 +13,16 @@ var (
 	FALSE = &object.Boolean{Value: false}
 )
 
+// 错误类别
+const (
+	RUNTIME_ERROR = "RuntimeError" // 默认类别，如内置函数参数错误
+	TYPE_ERROR    = "TypeError"    // 运算符或索引不支持操作数类型
+	NAME_ERROR    = "NameError"    // 标识符未定义
+	THROWN_ERROR  = "Error"        // throw抛出的非哈希值
+
+	GENERATOR_CLOSED = "GeneratorClosed" // 生成器被提前结束，不能被catch捕获
+)
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	// 语句
@@ -19,7 +30,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalProgram(node, env)
 
 	case *ast.BlockStatement:
-		return evalBlockStatement(node, env)
+		return evalBlockStatement(node, blockEnvironment(node, env))
 
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
@@ -31,12 +42,30 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return &object.ReturnValue{Value: val}
 
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return withPosition(newThrownError(val), node.Token)
+
 	case *ast.LetStatement:
 		val := Eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Token.Literal, val)
+		if node.Pattern != nil {
+			return evalDestructuring(node.Pattern, val, env, node.Const)
+		}
+		if err := bind(env, node.Name.Token.Literal, val, node.Const); err != nil {
+			return err
+		}
+
+	case *ast.StructStatement:
+		return evalStructStatement(node, env)
+
+	case *ast.EnumStatement:
+		return evalEnumStatement(node, env)
 
 	// 表达式
 	case *ast.IntegerLiteral:
@@ -48,41 +77,69 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 
+	case *ast.NullLiteral:
+		return NULL
+
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Token.Literal, right)
+		return withPosition(evalPrefixExpression(node.Token.Literal, right), node.Token)
 
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
+		if node.Token.Type == token.NULLISH { // 只在左侧为null时求值右侧
+			if left != NULL {
+				return left
+			}
+			return Eval(node.Right, env)
+		}
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
-		return evalInfixExpression(node.Token.Literal, left, right)
+		return withPosition(evalInfixExpression(node.Token.Literal, left, right), node.Token)
 
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
+	case *ast.ForExpression:
+		return evalForExpression(node, env)
+
+	case *ast.YieldStatement:
+		return evalYieldStatement(node, env)
+
+	case *ast.AwaitExpression:
+		return evalAwaitExpression(node, env)
+
+	case *ast.SelectExpression:
+		return evalSelectExpression(node, env)
+
+	case *ast.SwitchExpression:
+		return evalSwitchExpression(node, env)
+
 	case *ast.Identifier:
-		return evalIdentifier(node, env)
+		return withPosition(evalIdentifier(node, env), node.Token)
 
 	case *ast.FunctionLiteral:
-		params := node.Parameters
-		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return evalFunctionLiteral(node, env)
 
 	case *ast.CallExpression:
 		if node.Function.String() == "quote" {
 			return quote(node.Arguments[0], env)
 		}
 		function := Eval(node.Function, env) //得到object.Function对象
-		if isError(function) {
+		if isError(function) || isSkippedChain(function) {
 			return function
 		}
 
@@ -91,7 +148,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		keywords, err := evalKeywordArguments(node.KeywordArguments, env)
+		if err != nil {
+			return err
+		}
+
+		result := withPosition(applyFunctionWithKeywords(function, args, keywords), node.Token)
+		if fn, ok := function.(*object.Function); ok {
+			addStackFrame(result, fn, node.Token)
+		}
+		return result
 
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
@@ -102,15 +168,28 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
-		if isError(left) {
+		if isError(left) || isSkippedChain(left) {
 			return left
 		}
+		if node.Optional && left == NULL {
+			return &skippedChain{link: node}
+		}
 		index := Eval(node.Index, env)
 		if isError(index) {
 			return index
 		}
 		return evalIndexExpression(left, index)
 
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
+
+	case *ast.OptionalChain:
+		result := Eval(node.Expression, env)
+		if isSkippedChain(result) {
+			return NULL
+		}
+		return result
+
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
 	}
@@ -134,6 +213,27 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	return result
 }
 
+// let/const绑定名称，不能重新绑定同一环境中的常量
+func bind(env *object.Environment, name string, val object.Object, constant bool) *object.Error {
+	if !env.Define(name, val, constant) {
+		return newErrorOfKind(TYPE_ERROR, "cannot reassign constant %s", name)
+	}
+	return nil
+}
+
+// 块语句的词法作用域：块中有let/const、结构体或枚举声明时在新的内层环境中求值，
+// 块结束后绑定随之失效；没有绑定时直接使用外层环境
+// 函数体、循环体等已经有自己环境的块直接调用evalBlockStatement，不再多套一层
+func blockEnvironment(block *ast.BlockStatement, env *object.Environment) *object.Environment {
+	for _, statement := range block.Statements {
+		switch statement.(type) {
+		case *ast.LetStatement, *ast.StructStatement, *ast.EnumStatement:
+			return object.NewEnclosedEnvironment(env)
+		}
+	}
+	return env
+}
+
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
 
@@ -158,7 +258,7 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 	case "-":
 		return evalMinusPrefixOperatorExpression(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newErrorOfKind(TYPE_ERROR, "unknown operator: %s%s", operator, right.Type())
 	}
 }
 
@@ -177,7 +277,7 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+		return newErrorOfKind(TYPE_ERROR, "unknown operator: -%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
@@ -188,20 +288,26 @@ func evalInfixExpression(
 	operator string,
 	left, right object.Object,
 ) object.Object {
+	if result, ok := evalOperatorMethod(operator, left, right); ok {
+		return result
+	}
+
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
+	case (operator == "==" || operator == "!=") && left.Type() == object.ENUM_VALUE_OBJ:
+		return nativeBoolToBooleanObject(objectsEqual(left, right) == (operator == "=="))
 	case operator == "==":
 		return nativeBoolToBooleanObject(left == right)
 	case operator == "!=":
 		return nativeBoolToBooleanObject(left != right)
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s",
+		return newErrorOfKind(TYPE_ERROR, "type mismatch: %s %s %s",
 			left.Type(), operator, right.Type())
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newErrorOfKind(TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -210,7 +316,7 @@ func evalStringInfixExpression(
 	left, right object.Object,
 ) object.Object {
 	if operator != "+" {
-		return newError("unknown operator: %s %s %s",
+		return newErrorOfKind(TYPE_ERROR, "unknown operator: %s %s %s",
 			left.Type(), operator, right.Type())
 	}
 
@@ -243,7 +349,7 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 		return builtin
 	}
 
-	return newError("identifier not found: " + node.Token.Literal)
+	return newErrorOfKind(NAME_ERROR, "identifier not found: "+node.Token.Literal)
 }
 
 func evalIntegerInfixExpression(
@@ -271,7 +377,7 @@ func evalIntegerInfixExpression(
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newErrorOfKind(TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -296,7 +402,21 @@ func isTruthy(obj object.Object) bool {
 }
 
 func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return newErrorOfKind(RUNTIME_ERROR, format, a...)
+}
+
+func newErrorOfKind(kind string, format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: kind}
+}
+
+// 为尚未记录位置的错误补充位置信息
+// 错误向外层传递时，由最内层带词法单元的节点记录位置
+func withPosition(obj object.Object, tok token.Token) object.Object {
+	if err, ok := obj.(*object.Error); ok && err.Line == 0 {
+		err.Line = tok.Line
+		err.Column = tok.Column
+	}
+	return obj
 }
 
 func isError(obj object.Object) bool {
@@ -321,33 +441,207 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
+// 创建函数对象
+// 具名函数的闭包环境中绑定自身，使函数体内可以递归调用
+func evalFunctionLiteral(fl *ast.FunctionLiteral, env *object.Environment) object.Object {
+	fn := &object.Function{
+		Parameters: fl.Parameters,
+		Defaults:   fl.Defaults,
+		Rest:       fl.Rest,
+		Env:        env,
+		Body:       fl.Body,
+		Generator:  fl.Generator,
+		Async:      fl.Async,
+	}
+
+	if fl.Name != nil {
+		fn.Name = fl.Name.Token.Literal
+		fn.Env = object.NewEnclosedEnvironment(env)
+		fn.Env.Set(fn.Name, fn)
+	}
+
+	return fn
+}
+
+// 错误经过函数调用时记录调用栈
+func addStackFrame(obj object.Object, fn *object.Function, tok token.Token) {
+	err, ok := obj.(*object.Error)
+	if !ok {
+		return
+	}
+
+	name := fn.Name
+	if name == "" {
+		name = "<anonymous>"
+	}
+	err.Stack = append(err.Stack, fmt.Sprintf("%s (%d:%d)", name, tok.Line, tok.Column))
+}
+
 // 应用实参对象，计算函数值
+// 求值后的关键字参数，保持调用时的顺序
+type keywordArgument struct {
+	name  string
+	value object.Object
+}
+
+func evalKeywordArguments(kas []*ast.KeywordArgument, env *object.Environment) ([]keywordArgument, object.Object) {
+	var result []keywordArgument
+
+	for _, ka := range kas {
+		evaluated := Eval(ka.Value, env)
+		if isError(evaluated) {
+			return nil, evaluated
+		}
+		result = append(result, keywordArgument{name: ka.Name.Token.Literal, value: evaluated})
+	}
+
+	return result, nil
+}
+
 func applyFunction(fn object.Object, args []object.Object) object.Object {
+	return applyFunctionWithKeywords(fn, args, nil)
+}
+
+func applyFunctionWithKeywords(fn object.Object, args []object.Object, keywords []keywordArgument) object.Object {
 	switch fn := fn.(type) {
 
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
+		extendedEnv, err := extendFunctionEnv(fn, args, keywords)
+		if err != nil {
+			return err
+		}
+		if fn.Generator {
+			return newGenerator(fn, extendedEnv)
+		}
+		if fn.Async {
+			return callAsync(fn, extendedEnv)
+		}
+		evaluated := evalBlockStatement(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 
+	case *object.Struct:
+		return newInstance(fn, args, keywords)
+
+	case *object.Variant:
+		return newEnumValue(fn, args, keywords)
+
 	case *object.Builtin:
-		return fn.Fn(args...)
+		if len(fn.Keywords) == 0 {
+			if len(keywords) > 0 {
+				return newErrorOfKind(TYPE_ERROR, "unknown keyword argument `%s`", keywords[0].name)
+			}
+			return fn.Fn(args...)
+		}
+		options, err := builtinOptions(fn, keywords)
+		if err != nil {
+			return err
+		}
+		return fn.Fn(append(args, options)...)
 
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newErrorOfKind(TYPE_ERROR, "not a function: %s", fn.Type())
+	}
+}
+
+// 关键字参数转换为内置函数的选项哈希
+func builtinOptions(fn *object.Builtin, keywords []keywordArgument) (*object.Hash, *object.Error) {
+	pairs := make(map[object.HashKey]object.HashPair)
+
+	for _, kw := range keywords {
+		accepted := false
+		for _, name := range fn.Keywords {
+			if name == kw.name {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return nil, newErrorOfKind(TYPE_ERROR, "unknown keyword argument `%s`", kw.name)
+		}
+
+		key := &object.String{Value: kw.name}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: kw.value}
 	}
+
+	return &object.Hash{Pairs: pairs}, nil
+}
+
+// 取出内置函数实参末尾的选项哈希
+func splitOptions(args []object.Object) ([]object.Object, *object.Hash) {
+	return args[:len(args)-1], args[len(args)-1].(*object.Hash)
+}
+
+// 读取选项哈希中的值，不存在时返回nil
+func optionValue(options *object.Hash, name string) object.Object {
+	pair, ok := options.Pairs[(&object.String{Value: name}).HashKey()]
+	if !ok {
+		return nil
+	}
+	return pair.Value
 }
 
 // 扩展函数对象中环境变量
 // 传入实参对象，关联函数定义中的标识符参数
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+// 关键字参数按参数名绑定；缺少的实参使用默认值（可引用前面的参数），多余的实参收集到剩余参数中
+func extendFunctionEnv(fn *object.Function, args []object.Object, keywords []keywordArgument) (*object.Environment, *object.Error) {
+	tooFew := len(keywords) == 0 && len(args) < fn.RequiredParameters()
+	tooMany := fn.Rest == nil && len(args) > len(fn.Parameters)
+	if tooFew || tooMany {
+		if fn.Name != "" {
+			return nil, newErrorOfKind(TYPE_ERROR, "wrong number of arguments to `%s`. got=%d, want=%s",
+				fn.Name, len(args), fn.Arity())
+		}
+		return nil, newErrorOfKind(TYPE_ERROR, "wrong number of arguments. got=%d, want=%s",
+			len(args), fn.Arity())
+	}
+
+	bound := make([]object.Object, len(fn.Parameters))
+	copy(bound, args)
+
+	for _, kw := range keywords {
+		paramIdx := -1
+		for i, param := range fn.Parameters {
+			if param.Token.Literal == kw.name {
+				paramIdx = i
+				break
+			}
+		}
+		if paramIdx < 0 {
+			return nil, newErrorOfKind(TYPE_ERROR, "unknown keyword argument `%s`", kw.name)
+		}
+		if bound[paramIdx] != nil {
+			return nil, newErrorOfKind(TYPE_ERROR, "multiple values for argument `%s`", kw.name)
+		}
+		bound[paramIdx] = kw.value
+	}
+
 	env := object.NewEnclosedEnvironment(fn.Env)
 
 	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Token.Literal, args[paramIdx])
+		if bound[paramIdx] != nil {
+			env.Set(param.Token.Literal, bound[paramIdx])
+			continue
+		}
+
+		if fn.Defaults == nil || fn.Defaults[paramIdx] == nil {
+			return nil, newErrorOfKind(TYPE_ERROR, "missing argument `%s`", param.Token.Literal)
+		}
+		val := Eval(fn.Defaults[paramIdx], env)
+		if err, ok := val.(*object.Error); ok {
+			return nil, err
+		}
+		env.Set(param.Token.Literal, val)
 	}
 
-	return env
+	if fn.Rest != nil {
+		rest := []object.Object{}
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+		env.Set(fn.Rest.Token.Literal, &object.Array{Elements: rest})
+	}
+
+	return env, nil
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -359,13 +653,17 @@ func unwrapReturnValue(obj object.Object) object.Object {
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
+	if result, ok := callOperatorMethod(left, indexMethod, index); ok {
+		return result
+	}
+
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
-		return newError("index operator not supported: %s", left.Type())
+		return newErrorOfKind(TYPE_ERROR, "index operator not supported: %s", left.Type())
 	}
 }
 
@@ -393,9 +691,9 @@ func evalHashLiteral(
 			return key
 		}
 
-		hashKey, ok := key.(object.Hashable)
+		hashed, ok := object.HashKeyOf(key)
 		if !ok {
-			return newError("unusable as hash key: %s", key.Type())
+			return newErrorOfKind(TYPE_ERROR, "unusable as hash key: %s", key.Type())
 		}
 
 		value := Eval(valueNode, env)
@@ -403,7 +701,6 @@ func evalHashLiteral(
 			return value
 		}
 
-		hashed := hashKey.HashKey()
 		pairs[hashed] = object.HashPair{Key: key, Value: value}
 	}
 
@@ -413,12 +710,12 @@ func evalHashLiteral(
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
 
-	key, ok := index.(object.Hashable)
+	key, ok := object.HashKeyOf(index)
 	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
+		return newErrorOfKind(TYPE_ERROR, "unusable as hash key: %s", index.Type())
 	}
 
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Pairs[key]
 	if !ok {
 		return NULL
 	}
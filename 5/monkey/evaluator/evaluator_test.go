@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"monkey/ast"
 	"monkey/lexer"
 	"monkey/object"
 	"monkey/parser"
@@ -53,6 +54,20 @@ func testNullObject(t *testing.T, obj object.Object) bool {
 	return true
 }
 
+func testErrorObject(t *testing.T, obj object.Object, expected string) bool {
+	errObj, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("object is not Error. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q",
+			expected, errObj.Message)
+		return false
+	}
+	return true
+}
+
 func TestEvalIntegerExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -281,6 +296,33 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const a = 5; a;", 5},
+		{"const a = 5; let f = fn() { let a = 10; a }; f() + a", 15},
+		{"const a = 5; for (x in [1]) { const a = x; a }[0]", 1},
+		{"const [a, b] = [1, 2]; a + b", 3},
+		{"let a = 1; const a = 2; a", 2},
+		{"const a = 5; let a = 6;", "cannot reassign constant a"},
+		{"const a = 5; const a = 6;", "cannot reassign constant a"},
+		{"const a = 5; fn a() { 1 }", "cannot reassign constant a"},
+		{"const [a, b] = [1, 2]; let [c, b] = [3, 4];", "cannot reassign constant b"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fn(x) { x + 2; };"
 
@@ -324,6 +366,124 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
+func TestFunctionArity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let add = fn(a, b = 2) { a + b }; add(1)", 3},
+		{"let add = fn(a, b = 2) { a + b }; add(1, 5)", 6},
+		{"let f = fn(a = 1, b = a * 10) { a + b }; f()", 11},
+		{"let f = fn(a = 1, b = a * 10) { a + b }; f(2)", 22},
+		{"let f = fn(first, ...rest) { len(rest) }; f(1)", 0},
+		{"let f = fn(first, ...rest) { len(rest) }; f(1, 2, 3)", 2},
+		{"let f = fn(first, ...rest) { rest[1] }; f(1, 2, 3)", 3},
+		{"let f = fn(a, b = 10, ...rest) { a + b + len(rest) }; f(1)", 11},
+		{"let f = fn(a, b = 10, ...rest) { a + b + len(rest) }; f(1, 2, 3, 4)", 5},
+		{"let f = fn(a, b) { a + b }; f(1)", "wrong number of arguments. got=1, want=2"},
+		{"let f = fn(a, b) { a + b }; f(1, 2, 3)", "wrong number of arguments. got=3, want=2"},
+		{"let f = fn(a, b = 2) { a + b }; f()", "wrong number of arguments. got=0, want=1..2"},
+		{"let f = fn(a, ...rest) { a }; f()", "wrong number of arguments. got=0, want=1+"},
+		{"let f = fn(a = missing) { a }; f()", "identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestKeywordArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let f = fn(a, b) { a - b }; f(b: 1, a: 10)", 9},
+		{"let f = fn(a, b) { a - b }; f(10, b: 1)", 9},
+		{"let f = fn(a, b = 2, c = 3) { a * 100 + b * 10 + c }; f(1, c: 9)", 129},
+		{"let f = fn(a = 1, b = a * 10) { a + b }; f(b: 5)", 6},
+		{"let f = fn(a, ...rest) { a + len(rest) }; f(a: 1)", 1},
+		{"let f = fn(a, b) { a - b }; f(10, c: 1)", "unknown keyword argument `c`"},
+		{"let f = fn(a, b) { a - b }; f(10, a: 1)", "multiple values for argument `a`"},
+		{"let f = fn(a, b) { a - b }; f(b: 1)", "missing argument `a`"},
+		{"let f = fn(a, ...rest) { a }; f(rest: [])", "unknown keyword argument `rest`"},
+		{"len([1], verbose: true)", "unknown keyword argument `verbose`"},
+		{"let f = fn(a) { a }; f(a: missing)", "identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestNamedFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fn fact(n) { if (n < 2) { 1 } else { n * fact(n - 1) } }; fact(5)", 120},
+		{"let f = fn fib(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; f(10)", 55},
+		{"let f = fn inner(n) { n }; inner", "identifier not found: inner"},
+		{"fn add(a, b) { a + b }; add(1)", "wrong number of arguments to `add`. got=1, want=2"},
+		{"fn add(a, b) { a + b }; add", "<fn add/2>"},
+		{"fn opt(a, b = 1) { a }; opt", "<fn opt/1..2>"},
+		{"fn log(msg, ...args) { msg }; log", "<fn log/1+>"},
+		{"fn(x) { x }", "<fn/1>"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected && evaluated.Inspect() != "ERROR: "+expected {
+				t.Errorf("wrong result for %s. want=%q, got=%q",
+					tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	definitions := `fn inner(x) { x + true }
+fn outer(x) { inner(x) }
+let anon = fn() { outer(1) };
+`
+
+	evaluated := testEval(definitions + "anon()")
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expected := []string{"inner (2:20)", "outer (3:24)", "<anonymous> (4:5)"}
+	if len(errObj.Stack) != len(expected) {
+		t.Fatalf("wrong stack. want=%q, got=%q", expected, errObj.Stack)
+	}
+	for i, frame := range expected {
+		if errObj.Stack[i] != frame {
+			t.Errorf("wrong stack frame %d. want=%q, got=%q", i, frame, errObj.Stack[i])
+		}
+	}
+
+	caught := testEval(definitions + `try { anon() } catch (e) { e["stack"] }`)
+	if caught.Inspect() != "[inner (2:20), outer (3:24), <anonymous> (4:11)]" {
+		t.Errorf("wrong caught stack. got=%s", caught.Inspect())
+	}
+}
+
 func TestEnclosingEnvironments(t *testing.T) {
 	input := `
 let first = 10;
@@ -341,6 +501,56 @@ ourFunction(20) + first + second;`
 	testIntegerObject(t, testEval(input), 70)
 }
 
+func TestBlockScoping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"if (true) { let x = 1; x }", 1},
+		{"if (true) { let x = 1 }; x", "identifier not found: x"},
+		{"if (false) { 1 } else { let y = 2 }; y", "identifier not found: y"},
+		{"let x = 1; if (true) { let x = 2; x } + x", 3},
+		{"let x = 1; if (true) { let x = 2 }; x", 1},
+		{"const x = 1; if (true) { const x = 2; x }", 2},
+		{"let f = fn() { if (true) { let x = 1 }; x }; f()", "identifier not found: x"},
+		{"let f = fn(x) { let y = x; y }; f(3)", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestBlockEnvironment(t *testing.T) {
+	env := object.NewEnvironment()
+
+	tests := []struct {
+		input    string
+		newScope bool
+	}{
+		{"if (true) { 1 }", false},
+		{"if (true) { let x = 1 }", true},
+		{"if (true) { const x = 1 }", true},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		block := exp.(*ast.IfExpression).Consequence
+
+		if got := blockEnvironment(block, env) != env; got != tt.newScope {
+			t.Errorf("blockEnvironment(%q) new scope=%t, want=%t",
+				tt.input, got, tt.newScope)
+		}
+	}
+}
+
 func TestStringLiteral(t *testing.T) {
 	input := `"Hello World!"`
 
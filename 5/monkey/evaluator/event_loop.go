@@ -0,0 +1,392 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 单线程事件循环
+// set_timeout的回调和promise的回调都排队在这里，由RunEventLoop或await依次执行；
+// 定时器按SetClock设置的时钟计时，使用ManualClock时等待只推进虚拟时间
+type eventLoop struct {
+	mu         sync.Mutex
+	microtasks []func() // promise回调，先于定时器执行
+	timers     []*timer // 按到期时间排序，同时到期的按创建顺序
+	nextID     int64
+}
+
+type timer struct {
+	id       int64
+	due      time.Time
+	callback object.Object
+}
+
+var loop = &eventLoop{}
+
+func (l *eventLoop) enqueue(task func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.microtasks = append(l.microtasks, task)
+}
+
+func (l *eventLoop) addTimer(callback object.Object, delay time.Duration) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	t := &timer{id: l.nextID, due: currentClock().Now().Add(delay), callback: callback}
+
+	i := sort.Search(len(l.timers), func(i int) bool { return l.timers[i].due.After(t.due) })
+	l.timers = append(l.timers, nil)
+	copy(l.timers[i+1:], l.timers[i:])
+	l.timers[i] = t
+
+	return t.id
+}
+
+func (l *eventLoop) removeTimer(id int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, t := range l.timers {
+		if t.id == id {
+			l.timers = append(l.timers[:i], l.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// 取出下一个任务：先取promise回调，没有时等到最早的定时器到期
+// 没有任何任务时返回nil
+func (l *eventLoop) nextTask() func() object.Object {
+	l.mu.Lock()
+	if len(l.microtasks) > 0 {
+		task := l.microtasks[0]
+		l.microtasks = l.microtasks[1:]
+		l.mu.Unlock()
+		return func() object.Object { task(); return nil }
+	}
+	if len(l.timers) == 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	t := l.timers[0]
+	l.mu.Unlock()
+
+	c := currentClock()
+	if wait := t.due.Sub(c.Now()); wait > 0 {
+		c.Sleep(wait)
+	}
+	return func() object.Object {
+		if !l.removeTimer(t.id) {
+			return nil
+		}
+		return applyFunction(t.callback, nil)
+	}
+}
+
+// 执行任务直到done返回true或没有任务
+// 定时器回调中未捕获的错误会中止循环并返回，剩余任务仍然保留
+func (l *eventLoop) runUntil(done func() bool) *object.Error {
+	for done == nil || !done() {
+		task := l.nextTask()
+		if task == nil {
+			return nil
+		}
+		if err, ok := task().(*object.Error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// 执行事件循环中所有排队的回调和定时器，直到没有任务为止
+// 返回定时器回调中未捕获的错误，此时可以再次调用继续执行剩余任务
+// 仍在等待的async函数保持挂起，宿主之后确定promise时照常恢复
+func RunEventLoop() *object.Error {
+	return loop.runUntil(nil)
+}
+
+// 确定promise的结果，并把等待它的回调排入事件循环
+func settlePromise(p *object.Promise, state object.PromiseState, value object.Object) {
+	callbacks, ok := p.Settle(state, value)
+	if !ok {
+		return
+	}
+	for _, callback := range callbacks {
+		loop.enqueue(callback)
+	}
+}
+
+// 以value完成promise，value是promise时跟随它的结果
+func resolvePromise(p *object.Promise, value object.Object) {
+	if other, ok := value.(*object.Promise); ok && other != p {
+		other.OnAbandoned(func() { abandonPromise(p) })
+		onSettled(other, func(state object.PromiseState, value object.Object) {
+			settlePromise(p, state, value)
+		})
+		return
+	}
+	settlePromise(p, object.PROMISE_FULFILLED, value)
+}
+
+// promise结果确定后（在事件循环中）调用callback
+func onSettled(p *object.Promise, callback func(object.PromiseState, object.Object)) {
+	task := func() {
+		state, value := p.State()
+		callback(state, value)
+	}
+	if !p.OnSettled(task) {
+		loop.enqueue(task)
+	}
+}
+
+// 放弃再也不会被确定的promise：等待它的async函数从await处退出，
+// 跟随它的promise同样被放弃
+func abandonPromise(p *object.Promise) {
+	for _, callback := range p.Abandon() {
+		callback()
+	}
+}
+
+// rejected的原因转换为await得到的错误
+func rejectionToError(reason object.Object) object.Object {
+	if err, ok := reason.(*object.Error); ok {
+		return err
+	}
+	return newThrownError(reason)
+}
+
+// 调用async函数：立即执行函数体直到第一个await，返回代表函数结果的promise
+// 每次await的promise确定后，在事件循环中恢复函数体
+func callAsync(fn *object.Function, env *object.Environment) *object.Promise {
+	promise := object.NewPromise()
+	co := newCoroutine(env, asyncContext)
+
+	var result object.Object
+	body := fn.Body
+	co.run = func() {
+		defer close(co.yields)
+		result = unwrapReturnValue(evalBlockStatement(body, env))
+	}
+
+	var step func(sent object.Object)
+	step = func(sent object.Object) {
+		awaited, done := co.next(sent)
+		if done {
+			if err, ok := result.(*object.Error); ok {
+				settlePromise(promise, object.PROMISE_REJECTED, err)
+			} else if result == nil {
+				resolvePromise(promise, NULL)
+			} else {
+				resolvePromise(promise, result)
+			}
+			return
+		}
+
+		p, ok := awaited.(*object.Promise)
+		if !ok {
+			loop.enqueue(func() { step(awaited) })
+			return
+		}
+		p.OnAbandoned(func() {
+			co.close()
+			abandonPromise(promise)
+		})
+		onSettled(p, func(state object.PromiseState, value object.Object) {
+			if state == object.PROMISE_REJECTED {
+				value = rejectionToError(value)
+			}
+			step(value)
+		})
+	}
+	step(NULL)
+
+	return promise
+}
+
+// 求值await表达式
+// 在async函数中暂停函数体，等待promise确定后恢复；
+// 在顶层代码中直接运行事件循环直到promise确定
+// promise被拒绝时返回错误，可以被try/catch捕获；await非promise的值直接得到该值
+func evalAwaitExpression(ae *ast.AwaitExpression, env *object.Environment) object.Object {
+	val := Eval(ae.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	if co, ok := env.Get(asyncContext); ok {
+		return co.(*coroutine).yield(val)
+	}
+
+	p, ok := val.(*object.Promise)
+	if !ok {
+		return val
+	}
+
+	settled := func() bool {
+		state, _ := p.State()
+		return state != object.PROMISE_PENDING
+	}
+	if err := loop.runUntil(settled); err != nil {
+		return err
+	}
+
+	state, value := p.State()
+	switch state {
+	case object.PROMISE_FULFILLED:
+		return value
+	case object.PROMISE_REJECTED:
+		return rejectionToError(value)
+	default:
+		return withPosition(newError("await on a promise that never settles"), ae.Token)
+	}
+}
+
+func init() {
+	builtins["promise"] = &object.Builtin{Fn: newPromiseBuiltin}
+	builtins["then"] = &object.Builtin{Fn: then}
+}
+
+// set_timeout(fn, ms)
+// ms毫秒后在事件循环中调用fn，返回定时器编号
+func setTimeout(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	switch args[0].(type) {
+	case *object.Function, *object.Builtin:
+	default:
+		return newError("first argument to `set_timeout` must be FUNCTION, got %s",
+			args[0].Type())
+	}
+	ms, ok := args[1].(*object.Integer)
+	if !ok {
+		return newError("second argument to `set_timeout` must be INTEGER, got %s",
+			args[1].Type())
+	}
+
+	delay := time.Duration(ms.Value) * time.Millisecond
+	if delay < 0 {
+		delay = 0
+	}
+
+	return &object.Integer{Value: loop.addTimer(args[0], delay)}
+}
+
+// clear_timeout(id)
+// 取消尚未执行的定时器，返回是否取消成功
+func clearTimeout(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	id, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `clear_timeout` must be INTEGER, got %s",
+			args[0].Type())
+	}
+
+	return nativeBoolToBooleanObject(loop.removeTimer(id.Value))
+}
+
+// promise的resolve和reject共同引用的对象
+// 两者都被回收且都没有被调用过时，promise再也不会被确定，此时放弃promise；
+// 是否放弃只取决于resolve和reject还能否被调用，与事件循环中是否还有任务无关
+type promiseResolver struct {
+	promise *object.Promise
+	called  atomic.Bool
+}
+
+// promise(fn(resolve, reject) { ... })
+// 创建promise，立即以resolve和reject两个函数调用fn；fn中的错误使promise被拒绝
+func newPromiseBuiltin(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+
+	p := object.NewPromise()
+	r := &promiseResolver{promise: p}
+	runtime.SetFinalizer(r, func(r *promiseResolver) {
+		if !r.called.Load() {
+			abandonPromise(r.promise)
+		}
+	})
+
+	resolve := &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		value := object.Object(NULL)
+		if len(args) > 0 {
+			value = args[0]
+		}
+		r.called.Store(true)
+		resolvePromise(p, value)
+		return NULL
+	}}
+	reject := &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		reason := object.Object(NULL)
+		if len(args) > 0 {
+			reason = args[0]
+		}
+		r.called.Store(true)
+		settlePromise(p, object.PROMISE_REJECTED, reason)
+		return NULL
+	}}
+
+	if result := applyFunction(args[0], []object.Object{resolve, reject}); isError(result) {
+		settlePromise(p, object.PROMISE_REJECTED, result)
+	}
+
+	return p
+}
+
+// then(promise, on_fulfilled) 或 then(promise, on_fulfilled, on_rejected)
+// on_rejected得到拒绝的原因，原因是错误时得到与catch (e)相同的哈希
+// 返回新的promise，其结果为回调的返回值；回调中的错误使新promise被拒绝
+func then(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=2 or 3",
+			len(args))
+	}
+	p, ok := args[0].(*object.Promise)
+	if !ok {
+		return newError("first argument to `then` must be PROMISE, got %s",
+			args[0].Type())
+	}
+
+	handlers := map[object.PromiseState]object.Object{object.PROMISE_FULFILLED: args[1]}
+	if len(args) == 3 {
+		handlers[object.PROMISE_REJECTED] = args[2]
+	}
+
+	next := object.NewPromise()
+	p.OnAbandoned(func() { abandonPromise(next) })
+	onSettled(p, func(state object.PromiseState, value object.Object) {
+		handler, ok := handlers[state]
+		if !ok {
+			settlePromise(next, state, value)
+			return
+		}
+
+		// 错误导致的拒绝以catch得到的哈希传给on_rejected，直接传错误对象会被当作新的错误抛出
+		if err, ok := value.(*object.Error); ok && state == object.PROMISE_REJECTED {
+			value = errorToHash(err)
+		}
+
+		result := applyFunction(handler, []object.Object{value})
+		if isError(result) {
+			settlePromise(next, object.PROMISE_REJECTED, result)
+			return
+		}
+		resolvePromise(next, result)
+	})
+
+	return next
+}
@@ -0,0 +1,155 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"runtime"
+	"testing"
+	"time"
+)
+
+const delayFn = `let delay = fn(ms, v) { promise(fn(resolve, reject) { set_timeout(fn() { resolve(v) }, ms) }) };
+`
+
+func TestEventLoop(t *testing.T) {
+	SetClock(NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	defer SetClock(nil)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let log = channel(10);
+		  set_timeout(fn() { send(log, "b") }, 20);
+		  set_timeout(fn() { send(log, "a") }, 10);
+		  set_timeout(fn() { send(log, "c") }, 20);
+		  let start = now();
+		  let elapsed = await promise(fn(resolve, reject) { set_timeout(fn() { resolve(now() - start) }, 30) });
+		  close(log);
+		  [elapsed, for (x in log) { x }]`, "[30, [a, b, c]]"},
+		{`let log = channel(10);
+		  let id = set_timeout(fn() { send(log, 1) }, 10);
+		  let cleared = [clear_timeout(id), clear_timeout(id)];
+		  await delay(20, 0);
+		  close(log);
+		  [cleared, for (x in log) { x }]`, "[[true, false], []]"},
+		{`async fn add() { let a = await delay(10, 1); let b = await delay(5, 2); a + b };
+		  let start = now();
+		  [await add(), now() - start]`, "[3, 15]"},
+		{`let log = channel(10);
+		  async fn f() { send(log, "start"); await 1; send(log, "after") };
+		  let p = f();
+		  send(log, "sync");
+		  await p;
+		  close(log);
+		  for (x in log) { x }`, "[start, sync, after]"},
+		{`async fn f() { try { await promise(fn(res, rej) { rej("boom") }) } catch (e) { e["message"] } };
+		  await f()`, "boom"},
+		{`async fn f() { missing };
+		  let p = f();
+		  [try { await p } catch (e) { e["message"] }, p]`,
+			"[identifier not found: missing, <promise rejected: ERROR: identifier not found: missing>]"},
+		{`let f = async fn(x) { return x * 2 }; await f(21)`, "42"},
+		{`await then(delay(5, 2), fn(x) { x * 10 })`, "20"},
+		{`await then(promise(fn(res, rej) { rej("no") }), fn(x) { x }, fn(r) { r + "!" })`, "no!"},
+		{`async fn f() { throw "boom" }; await then(f(), fn(v) { v }, fn(e) { "handled: " + e["message"] })`, "handled: boom"},
+		{`async fn f() { missing }; await then(f(), fn(v) { v }, fn(e) { e["message"] })`, "identifier not found: missing"},
+		{`await promise(fn(res, rej) { res(delay(5, 7)) })`, "7"},
+		{`await 5`, "5"},
+		{`promise(fn(res, rej) { res(1) })`, "<promise fulfilled: 1>"},
+		{`promise(fn(res, rej) { })`, "<promise pending>"},
+		{`async fn f() { 1 }; f`, "<fn f/0>"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(delayFn + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+		RunEventLoop()
+	}
+}
+
+func TestEventLoopErrors(t *testing.T) {
+	SetClock(NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	defer SetClock(nil)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"await promise(fn(res, rej) { })", "await on a promise that never settles"},
+		{"set_timeout(fn() { missing }, 5); await delay(10, 1)", "identifier not found: missing"},
+		{"await promise(fn(res, rej) { throw \"bad\" })", "bad"},
+		{"await then(delay(1, 1), fn(x) { x + true })", "type mismatch: INTEGER + BOOLEAN"},
+		{"set_timeout(1, 5)", "first argument to `set_timeout` must be FUNCTION, got INTEGER"},
+		{"then(1, fn(x) { x })", "first argument to `then` must be PROMISE, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(delayFn+tt.input), tt.expected)
+		RunEventLoop()
+	}
+}
+
+func TestRunEventLoop(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	manual := NewManualClock(start)
+	SetClock(manual)
+	defer SetClock(nil)
+
+	env := object.NewEnvironment()
+	Eval(testParseProgram(`let log = channel(5);
+		set_timeout(fn() { send(log, 1); set_timeout(fn() { send(log, 2) }, 500) }, 1000);
+		set_timeout(fn() { missing }, 2000);`), env)
+
+	if err := RunEventLoop(); err == nil || err.Message != "identifier not found: missing" {
+		t.Fatalf("wrong error from RunEventLoop. got=%+v", err)
+	}
+	if elapsed := manual.Now().Sub(start); elapsed != 2*time.Second {
+		t.Errorf("wrong virtual time elapsed. got=%s", elapsed)
+	}
+	if err := RunEventLoop(); err != nil {
+		t.Errorf("unexpected error from RunEventLoop. got=%+v", err)
+	}
+
+	evaluated := Eval(testParseProgram("[recv(log), recv(log)]"), env)
+	if evaluated.Inspect() != "[1, 2]" {
+		t.Errorf("wrong callbacks run. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestAsyncResumesAfterEventLoopDrains(t *testing.T) {
+	// 宿主（如REPL）在事件循环没有任务后才确定promise
+	env := object.NewEnvironment()
+	inputs := []string{
+		`let ch = channel(1); let p = promise(fn(res, rej) { send(ch, res) }); async fn f() { let v = await p; v * 2 }; let q = f();`,
+		`recv(ch)(21)`,
+		`await q`,
+	}
+
+	var evaluated object.Object
+	for _, input := range inputs {
+		evaluated = Eval(testParseProgram(input), env)
+		if err := RunEventLoop(); err != nil {
+			t.Fatalf("unexpected error from RunEventLoop. got=%+v", err)
+		}
+		runtime.GC()
+	}
+	testIntegerObject(t, evaluated, 42)
+}
+
+func TestAbandonedPromisesReleaseAsync(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	for i := 0; i < 20; i++ {
+		testEval(`async fn f() { await promise(fn(r, j) { }) }; async fn g() { await f(); 1 }; g()`)
+		testEval(`async fn f() { await then(promise(fn(r, j) { }), fn(x) { x }) }; f()`)
+		testEval(`async fn f() { await promise(fn(r, j) { r(promise(fn(r2, j2) { })) }) }; f()`)
+		RunEventLoop()
+	}
+
+	waitForGoroutines(baseline)
+	if n := runtime.NumGoroutine(); n > baseline {
+		t.Errorf("async goroutines leaked. baseline=%d, got=%d", baseline, n)
+	}
+}
@@ -0,0 +1,254 @@
+package evaluator
+
+import (
+	"errors"
+	"monkey/object"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 文件系统能力配置
+// 默认关闭，嵌入方通过EnableFileSystem开启，并限定允许访问的根目录
+type FileSystemConfig struct {
+	Roots []string // 允许访问的根目录
+}
+
+// 当前生效的根目录（绝对路径），nil表示未开启
+// 开启和关闭可能与spawn启动的任务并发，读写都需要加锁
+var (
+	fileSystemMu    sync.RWMutex
+	fileSystemRoots []string
+)
+
+// 开启文件系统内置函数，只允许访问config.Roots下的文件
+func EnableFileSystem(config FileSystemConfig) error {
+	if len(config.Roots) == 0 {
+		return errors.New("file system config needs at least one root")
+	}
+
+	roots := make([]string, 0, len(config.Roots))
+	for _, root := range config.Roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		roots = append(roots, abs)
+	}
+
+	fileSystemMu.Lock()
+	defer fileSystemMu.Unlock()
+	fileSystemRoots = roots
+	return nil
+}
+
+// 关闭文件系统内置函数
+func DisableFileSystem() {
+	fileSystemMu.Lock()
+	defer fileSystemMu.Unlock()
+	fileSystemRoots = nil
+}
+
+// 当前生效的根目录，返回的切片不会再被修改
+func allowedRoots() []string {
+	fileSystemMu.RLock()
+	defer fileSystemMu.RUnlock()
+	return fileSystemRoots
+}
+
+// 解析脚本传入的路径，并检查是否位于允许的根目录下
+// 相对路径以第一个根目录为基准；返回解析符号链接后的路径，检查和后续读写使用同一路径
+func resolvePath(name string, arg object.Object) (string, *object.Error) {
+	roots := allowedRoots()
+	if roots == nil {
+		return "", newError("file system access is disabled: `%s`", name)
+	}
+	str, ok := arg.(*object.String)
+	if !ok {
+		return "", newError("argument to `%s` must be STRING, got %s", name, arg.Type())
+	}
+
+	path := str.Value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(roots[0], path)
+	}
+
+	resolved, err := resolveSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", newError("%s: %s", name, err)
+	}
+	for _, root := range roots {
+		if withinRoot(resolved, root) {
+			return resolved, nil
+		}
+	}
+
+	return "", newError("access denied: %s is outside the allowed roots", str.Value)
+}
+
+// 解析一个路径最多经过的符号链接数
+const maxSymlinks = 255
+
+// 逐级解析绝对路径中的符号链接，悬空链接也解析到其目标
+// 不存在的部分原样保留
+func resolveSymlinks(path string) (string, error) {
+	volume := filepath.VolumeName(path)
+	resolved := volume + string(filepath.Separator)
+	rest := splitPath(path[len(volume):])
+
+	links := 0
+	for len(rest) > 0 {
+		part := rest[0]
+		rest = rest[1:]
+
+		if part == "." {
+			continue
+		}
+		if part == ".." {
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+		info, err := os.Lstat(next)
+		if os.IsNotExist(err) || err == nil && info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", errors.New("too many levels of symbolic links")
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			volume = filepath.VolumeName(target)
+			resolved = volume + string(filepath.Separator)
+			target = target[len(volume):]
+		}
+		rest = append(splitPath(target), rest...)
+	}
+
+	return resolved, nil
+}
+
+// 按分隔符切分路径，忽略空的部分
+func splitPath(path string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func withinRoot(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// read_file(path)
+// 读取文件内容
+func readFile(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	path, errObj := resolvePath("read_file", args[0])
+	if errObj != nil {
+		return errObj
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newError("read_file: %s", err)
+	}
+
+	return &object.String{Value: string(data)}
+}
+
+// write_file(path, content)
+// 写入文件，已存在则覆盖
+func writeFile(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	path, errObj := resolvePath("write_file", args[0])
+	if errObj != nil {
+		return errObj
+	}
+	content, ok := args[1].(*object.String)
+	if !ok {
+		return newError("second argument to `write_file` must be STRING, got %s",
+			args[1].Type())
+	}
+
+	if err := os.WriteFile(path, []byte(content.Value), 0644); err != nil {
+		return newError("write_file: %s", err)
+	}
+
+	return NULL
+}
+
+// list_dir(path)
+// 列出目录下的文件名，按字典序排列
+func listDir(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	path, errObj := resolvePath("list_dir", args[0])
+	if errObj != nil {
+		return errObj
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return newError("list_dir: %s", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	elements := make([]object.Object, len(names))
+	for i, n := range names {
+		elements[i] = &object.String{Value: n}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// exists(path)
+// 判断文件或目录是否存在
+func fileExists(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	path, errObj := resolvePath("exists", args[0])
+	if errObj != nil {
+		return errObj
+	}
+
+	_, err := os.Stat(path)
+	return nativeBoolToBooleanObject(err == nil)
+}
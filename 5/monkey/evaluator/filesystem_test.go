@@ -0,0 +1,146 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSystemDisabledByDefault(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`read_file("a.txt")`, "file system access is disabled: `read_file`"},
+		{`write_file("a.txt", "x")`, "file system access is disabled: `write_file`"},
+		{`list_dir(".")`, "file system access is disabled: `list_dir`"},
+		{`exists("a.txt")`, "file system access is disabled: `exists`"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFileSystemBuiltins(t *testing.T) {
+	root := t.TempDir()
+	if err := EnableFileSystem(FileSystemConfig{Roots: []string{root}}); err != nil {
+		t.Fatalf("EnableFileSystem failed: %s", err)
+	}
+	defer DisableFileSystem()
+
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	input := `
+write_file("b.txt", "bee");
+write_file("a.txt", "hello");
+[exists("a.txt"), exists("missing.txt"), read_file("a.txt"), list_dir(".")]`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	testBooleanObject(t, result.Elements[0], true)
+	testBooleanObject(t, result.Elements[1], false)
+	if content := result.Elements[2].Inspect(); content != "hello" {
+		t.Errorf("read_file returned wrong content. got=%q", content)
+	}
+	if listing := result.Elements[3].Inspect(); listing != "[a.txt, b.txt, sub]" {
+		t.Errorf("list_dir returned wrong entries. got=%q", listing)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "b.txt"))
+	if err != nil || string(data) != "bee" {
+		t.Errorf("write_file did not write file. got=%q (%v)", data, err)
+	}
+}
+
+func TestFileSystemSandbox(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(base, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	// 悬空链接：目标不存在时也不能借此在根目录外创建文件
+	if err := os.Symlink(filepath.Join(base, "escaped.txt"), filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("dangling", filepath.Join(root, "chained")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("loop", filepath.Join(root, "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnableFileSystem(FileSystemConfig{Roots: []string{root}}); err != nil {
+		t.Fatalf("EnableFileSystem failed: %s", err)
+	}
+	defer DisableFileSystem()
+
+	secret := filepath.Join(base, "secret.txt")
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`read_file("../secret.txt")`, "access denied: ../secret.txt is outside the allowed roots"},
+		{`read_file("` + secret + `")`, "access denied: " + secret + " is outside the allowed roots"},
+		{`read_file("escape/secret.txt")`, "access denied: escape/secret.txt is outside the allowed roots"},
+		{`write_file("escape/new.txt", "x")`, "access denied: escape/new.txt is outside the allowed roots"},
+		{`write_file("dangling", "pwned")`, "access denied: dangling is outside the allowed roots"},
+		{`write_file("chained", "pwned")`, "access denied: chained is outside the allowed roots"},
+		{`exists("dangling")`, "access denied: dangling is outside the allowed roots"},
+		{`read_file("loop")`, "read_file: too many levels of symbolic links"},
+		{`read_file(1)`, "argument to `read_file` must be STRING, got INTEGER"},
+		{`write_file("a.txt", 1)`, "second argument to `write_file` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+
+	// 指向根目录内的悬空链接仍然可以写入
+	if err := os.Symlink("target.txt", filepath.Join(root, "inside")); err != nil {
+		t.Fatal(err)
+	}
+	testNullObject(t, testEval(`write_file("inside", "ok")`))
+	if data, err := os.ReadFile(filepath.Join(root, "target.txt")); err != nil || string(data) != "ok" {
+		t.Errorf("write_file through link inside root failed. got=%q (%v)", data, err)
+	}
+
+	for _, name := range []string{"new.txt", "escaped.txt"} {
+		if _, err := os.Stat(filepath.Join(base, name)); !os.IsNotExist(err) {
+			t.Errorf("write_file escaped the sandbox: %s", name)
+		}
+	}
+}
+
+func TestEnableFileSystemWhileTasksRun(t *testing.T) {
+	root := t.TempDir()
+	defer DisableFileSystem()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if err := EnableFileSystem(FileSystemConfig{Roots: []string{root}}); err != nil {
+				t.Error(err)
+				return
+			}
+			DisableFileSystem()
+		}
+	}()
+
+	testEval("let tasks = for (i in range(10)) { spawn(fn() { try { exists(\"a.txt\") } catch { false } }) }; for (t in tasks) { join(t) }")
+	<-done
+}
@@ -0,0 +1,174 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+	"runtime"
+	"sync"
+)
+
+// 协程在函数环境中的名称，yield和await是关键字，不会与用户变量冲突
+const (
+	generatorContext = "yield"
+	asyncContext     = "await"
+)
+
+// 协程，生成器和async函数的函数体在单独的goroutine中执行，与调用方通过通道交替运行：
+// 调用方每次恢复执行后等待函数体交出的下一个值，函数体交出值后等待下一次恢复
+type coroutine struct {
+	run func() // 执行函数体，首次恢复时启动，结束时关闭yields
+
+	yields chan object.Object
+	resume chan object.Object
+	stop   chan struct{}
+
+	mu       sync.Mutex
+	started  bool
+	finished bool
+	stopOnce sync.Once
+}
+
+func (co *coroutine) Type() object.ObjectType { return "COROUTINE" }
+func (co *coroutine) Inspect() string         { return "coroutine" }
+
+// 创建协程并记录在函数环境中，函数体通过该名称找到所在的协程
+func newCoroutine(env *object.Environment, context string) *coroutine {
+	co := &coroutine{
+		yields: make(chan object.Object),
+		resume: make(chan object.Object),
+		stop:   make(chan struct{}),
+	}
+	env.Set(context, co)
+	return co
+}
+
+// 恢复函数体的执行，sent作为函数体中yield/await的值，返回函数体交出的下一个值；
+// 函数体执行完毕时done为true
+func (co *coroutine) next(sent object.Object) (value object.Object, done bool) {
+	if !co.mu.TryLock() {
+		return newError("generator is already running"), false
+	}
+	defer co.mu.Unlock()
+
+	select {
+	case <-co.stop:
+		co.finished = true
+	default:
+	}
+	if co.finished {
+		return nil, true
+	}
+
+	if !co.started {
+		co.started = true
+		go co.run()
+	} else {
+		select {
+		case co.resume <- sent:
+		case <-co.stop:
+			co.finished = true
+			return nil, true
+		}
+	}
+
+	value, ok := <-co.yields
+	if !ok {
+		co.finished = true
+		return nil, true
+	}
+	if isError(value) {
+		co.finished = true
+	}
+
+	return value, false
+}
+
+// 结束协程，正在等待恢复的函数体从yield处返回GENERATOR_CLOSED错误，
+// 经过finally块后退出
+func (co *coroutine) close() {
+	co.stopOnce.Do(func() { close(co.stop) })
+}
+
+// 把值交给调用方并等待下一次恢复，返回恢复时传入的值
+func (co *coroutine) yield(value object.Object) object.Object {
+	select {
+	case co.yields <- value:
+	case <-co.stop:
+		return newErrorOfKind(GENERATOR_CLOSED, "generator closed")
+	}
+
+	select {
+	case sent := <-co.resume:
+		return sent
+	case <-co.stop:
+		return newErrorOfKind(GENERATOR_CLOSED, "generator closed")
+	}
+}
+
+// 调用生成器函数，绑定实参后返回尚未开始执行的生成器
+// 生成器被提前结束或被垃圾回收时，函数体的goroutine会从当前的yield处退出；
+// 生成器保存在定义它的环境中时，goroutine经由环境引用着生成器，只能通过close或提前结束的迭代释放
+func newGenerator(fn *object.Function, env *object.Environment) *object.Generator {
+	co := newCoroutine(env, generatorContext)
+
+	body := fn.Body
+	co.run = func() {
+		defer close(co.yields)
+
+		// 函数体中的错误作为最后一个值交给调用方
+		result := evalBlockStatement(body, env)
+		if err, ok := result.(*object.Error); ok && err.Kind != GENERATOR_CLOSED {
+			select {
+			case co.yields <- err:
+			case <-co.stop:
+			}
+		}
+	}
+
+	resume := func() (object.Object, bool) { return co.next(NULL) }
+	gen := &object.Generator{Name: fn.Name, Resume: resume, Stop: co.close}
+	runtime.SetFinalizer(gen, func(*object.Generator) { co.close() })
+
+	return gen
+}
+
+func evalYieldStatement(ys *ast.YieldStatement, env *object.Environment) object.Object {
+	val := Eval(ys.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	co, ok := env.Get(generatorContext)
+	if !ok {
+		return withPosition(newError("yield outside generator"), ys.Token)
+	}
+
+	if result := co.(*coroutine).yield(val); isError(result) {
+		return result
+	}
+	return NULL
+}
+
+// next(generator) 或 next(generator, default)
+// 返回生成器的下一个值，生成器结束后返回default（默认为null）
+func generatorNext(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	gen, ok := args[0].(*object.Generator)
+	if !ok {
+		return newError("argument to `next` must be GENERATOR, got %s",
+			args[0].Type())
+	}
+
+	value, done := gen.Resume()
+	if done {
+		if len(args) == 2 {
+			return args[1]
+		}
+		return NULL
+	}
+
+	return value
+}
@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let g = fn() { yield 1; yield 2; }(); [next(g), next(g), next(g), next(g, -1)]", "[1, 2, null, -1]"},
+		{"let squares = fn(n) { for (i in range(n)) { yield i * i } }; for (x in squares(4)) { x }", "[0, 1, 4, 9]"},
+		{"let squares = fn(n) { for (i in range(n)) { yield i * i } }; for (i, x in squares(3)) { [i, x] }", "[[0, 0], [1, 1], [2, 4]]"},
+		{"let naturals = fn() { for (i in range(0, 9223372036854775807)) { yield i } }; let g = naturals(); next(g); next(g); next(g)", "2"},
+		{"fn nat(n) { yield n; for (x in nat(n + 1)) { yield x } }; let g = nat(1); next(g); next(g); next(g)", "3"},
+		{"let g = fn() { yield 1; throw \"boom\" }(); next(g)", "1"},
+		{"let g = fn() { yield 1; yield 2 }(); next(g); close(g); next(g, \"done\")", "done"},
+		{"let g = fn() { yield 1 }(); close(g); next(g, \"done\")", "done"},
+		{"let g = fn() { try { yield 1 } catch { yield 2 } }(); next(g); close(g); next(g, \"done\")", "done"},
+		{"let outer = fn() { let inner = fn() { yield 1 }; inner }; outer()", "<fn/0>"},
+		{"fn count() { yield 1 }; count()", "<generator count>"},
+		{"let first = fn(g) { for (x in g) { return x } }; first(fn() { yield 7; yield 8 }())", "7"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let g = fn() { yield 1; throw \"boom\" }(); next(g); next(g)", "boom"},
+		{"let g = fn() { yield 1; missing }(); for (x in g) { x }", "identifier not found: missing"},
+		{"let g = fn(a) { yield a }; g()", "wrong number of arguments. got=0, want=1"},
+		{"next([1])", "argument to `next` must be GENERATOR, got ARRAY"},
+		{"close(1)", "argument to `close` must be CHANNEL or GENERATOR, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestAbandonedGeneratorsDoNotLeak(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	for i := 0; i < 20; i++ {
+		testEval("let take = fn(g) { next(g) }; take(fn() { yield 1; yield 2 }())")
+		testEval("let g = fn() { yield 1; yield 2 }(); next(g); close(g)")
+		testEval("let g = fn() { yield 1; yield 2 }(); let first = fn() { for (x in g) { return x } }; first()")
+	}
+
+	waitForGoroutines(baseline)
+	if n := runtime.NumGoroutine(); n > baseline {
+		t.Errorf("generator goroutines leaked. baseline=%d, got=%d", baseline, n)
+	}
+}
+
+func TestGeneratorsSurviveGarbageCollection(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(1))
+
+	input := `let g = fn() { yield 1; yield 2 }();
+	next(g);
+	let junk = for (i in range(100000)) { [i, i, i] };
+	next(g, "closed")`
+	for i := 0; i < 5; i++ {
+		testIntegerObject(t, testEval(input), 2)
+	}
+
+	// 宿主持有的生成器在求值所用的环境被丢弃后仍然可以继续
+	gen := testEval("fn() { yield 1; yield 2; yield 3 }()").(*object.Generator)
+	values := []object.Object{}
+	for {
+		runtime.GC()
+		_, value, ok := gen.Next()
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+	if len(values) != 3 {
+		t.Errorf("generator stopped early. got=%v", values)
+	}
+}
+
+func TestEarlyExitClosesGenerator(t *testing.T) {
+	tests := []string{
+		"let g = fn() { yield 1; yield 2 }(); let first = fn() { for (x in g) { return x } }; first(); next(g, \"closed\")",
+		"let g = fn() { yield 1; yield 2 }(); try { for (x in g) { throw x } } catch { }; next(g, \"closed\")",
+		"let g = fn() { yield 1; yield 2 }(); try { map(g, fn(x) { throw x }) } catch { }; next(g, \"closed\")",
+		"let g = fn() { yield 1; yield 2 }(); try { reduce(g, fn(acc, x) { throw x }, 0) } catch { }; next(g, \"closed\")",
+	}
+
+	for _, input := range tests {
+		baseline := runtime.NumGoroutine()
+		env := object.NewEnvironment()
+		evaluated := Eval(testParseProgram(input), env)
+		if evaluated.Inspect() != "closed" {
+			t.Errorf("generator was not closed for %q. got=%q", input, evaluated.Inspect())
+		}
+
+		// env仍被引用，goroutine不是因为环境被回收才退出的
+		waitForGoroutines(baseline)
+		if n := runtime.NumGoroutine(); n > baseline {
+			t.Errorf("generator goroutine still running for %q. baseline=%d, got=%d", input, baseline, n)
+		}
+		runtime.KeepAlive(env)
+	}
+}
+
+// 等待goroutine数量回到baseline，期间反复触发垃圾回收
+func waitForGoroutines(baseline int) {
+	for i := 0; i < 100 && runtime.NumGoroutine() > baseline; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,191 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 求值for-in表达式
+// 每次迭代在新的环境中绑定循环变量并求值循环体，结果为各次循环体的值组成的数组；
+// 单变量形式绑定元素值，双变量形式同时绑定键（数组下标、哈希键）和值
+func evalForExpression(fe *ast.ForExpression, env *object.Environment) object.Object {
+	collection := Eval(fe.Iterable, env)
+	if isError(collection) {
+		return collection
+	}
+
+	iterable, ok := collection.(object.Iterable)
+	if !ok {
+		return newErrorOfKind(TYPE_ERROR, "cannot iterate over %s", collection.Type())
+	}
+
+	results := []object.Object{}
+
+	it := iterable.Iter()
+	for {
+		key, value, ok := it.Next()
+		if !ok {
+			break
+		}
+		if isError(value) {
+			closeIterator(it)
+			return value
+		}
+
+		loopEnv := object.NewEnclosedEnvironment(env)
+		if fe.Key != nil {
+			loopEnv.Set(fe.Key.Token.Literal, key)
+		}
+		loopEnv.Set(fe.Value.Token.Literal, value)
+
+		result := evalBlockStatement(fe.Body, loopEnv)
+		if result == nil {
+			result = NULL
+		}
+
+		rt := result.Type()
+		if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+			closeIterator(it)
+			return result
+		}
+
+		results = append(results, result)
+	}
+
+	return &object.Array{Elements: results}
+}
+
+// 迭代提前结束（return或错误）时关闭迭代器，生成器的goroutine随之退出
+func closeIterator(it object.Iterator) {
+	if closer, ok := it.(object.Closer); ok {
+		closer.Close()
+	}
+}
+
+func init() {
+	builtins["map"] = &object.Builtin{Fn: mapBuiltin}
+	builtins["filter"] = &object.Builtin{Fn: filterBuiltin}
+	builtins["reduce"] = &object.Builtin{Fn: reduceBuiltin}
+}
+
+// 检查map/filter/reduce的参数，并对可迭代对象的每个元素调用f
+// 迭代中或f返回的错误直接返回
+func eachElement(name string, collection, fn object.Object, f func(value object.Object) object.Object) object.Object {
+	iterable, ok := collection.(object.Iterable)
+	if !ok {
+		return newError("first argument to `%s` must be iterable, got %s",
+			name, collection.Type())
+	}
+	switch fn.(type) {
+	case *object.Function, *object.Builtin:
+	default:
+		return newError("second argument to `%s` must be FUNCTION, got %s",
+			name, fn.Type())
+	}
+
+	it := iterable.Iter()
+	for {
+		_, value, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		if isError(value) {
+			closeIterator(it)
+			return value
+		}
+		if result := f(value); isError(result) {
+			closeIterator(it)
+			return result
+		}
+	}
+}
+
+// map(iterable, fn)
+// 返回对每个元素调用fn的结果组成的数组
+func mapBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+
+	results := []object.Object{}
+	err := eachElement("map", args[0], args[1], func(value object.Object) object.Object {
+		result := applyFunction(args[1], []object.Object{value})
+		results = append(results, result)
+		return result
+	})
+	if err != nil {
+		return err
+	}
+
+	return &object.Array{Elements: results}
+}
+
+// filter(iterable, fn)
+// 返回fn结果为真的元素组成的数组
+func filterBuiltin(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+
+	results := []object.Object{}
+	err := eachElement("filter", args[0], args[1], func(value object.Object) object.Object {
+		result := applyFunction(args[1], []object.Object{value})
+		if isTruthy(result) {
+			results = append(results, value)
+		}
+		return result
+	})
+	if err != nil {
+		return err
+	}
+
+	return &object.Array{Elements: results}
+}
+
+// reduce(iterable, fn, initial)
+// 依次以累积值和元素调用fn，返回最终的累积值
+func reduceBuiltin(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=3",
+			len(args))
+	}
+
+	acc := args[2]
+	err := eachElement("reduce", args[0], args[1], func(value object.Object) object.Object {
+		acc = applyFunction(args[1], []object.Object{acc, value})
+		return acc
+	})
+	if err != nil {
+		return err
+	}
+
+	return acc
+}
+
+// range(end)、range(start, end) 或 range(start, end, step)
+// 返回惰性的整数区间，step默认为1
+func rangeBuiltin(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newError("wrong number of arguments. got=%d, want=1..3",
+			len(args))
+	}
+	values, errObj := integerArgs("range", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	r := &object.Range{End: values[0], Step: 1}
+	if len(values) >= 2 {
+		r.Start, r.End = values[0], values[1]
+	}
+	if len(values) == 3 {
+		r.Step = values[2]
+	}
+	if r.Step == 0 {
+		return newError("step to `range` must not be zero")
+	}
+
+	return r
+}
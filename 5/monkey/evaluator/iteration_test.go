@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestForExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x in [1, 2, 3]) { x * 2 }", "[2, 4, 6]"},
+		{"for (i, x in [10, 20]) { i + x }", "[10, 21]"},
+		{"for (x in []) { x }", "[]"},
+		{`for (k, v in {"b": 2, "a": 1}) { k + "=" + json_stringify(v) }`, "[a=1, b=2]"},
+		{`for (v in {2: "two", 1: "one"}) { v }`, "[one, two]"},
+		{`for (c in "abc") { c + c }`, "[aa, bb, cc]"},
+		{"for (i in range(3)) { i }", "[0, 1, 2]"},
+		{"for (i in range(1, 10, 4)) { i }", "[1, 5, 9]"},
+		{"for (i in range(3, 0, -1)) { i }", "[3, 2, 1]"},
+		{"for (x in [1, 2, 3]) { if (x > 1) { x } }", "[null, 2, 3]"},
+		{"let x = 100; for (x in [1]) { x }; x", "100"},
+		{"let find = fn(xs) { for (x in xs) { if (x > 1) { return x } }; 0 }; find([1, 5, 7])", "5"},
+		{"range(1, 5, 2)", "range(1, 5, 2)"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestForExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x in 5) { x }", "cannot iterate over INTEGER"},
+		{"for (x in [1, 2]) { x + true }", "type mismatch: INTEGER + BOOLEAN"},
+		{"for (x in missing) { x }", "identifier not found: missing"},
+		{"range(1, 2, 0)", "step to `range` must not be zero"},
+		{`range("a")`, "argument to `range` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestMapFilterReduce(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1, 2, 3], |x| x * 2)", "[2, 4, 6]"},
+		{"filter(range(6), |x| x / 2 * 2 == x)", "[0, 2, 4]"},
+		{"reduce([1, 2, 3], |acc, x| acc + x, 10)", "16"},
+		{"reduce([], |acc, x| acc + x, 0)", "0"},
+		{`map("ab", |c| c + c)`, "[aa, bb]"},
+		{"map([[1], [2, 3]], len)", "[1, 2]"},
+		{"[1, 2, 3, 4] |> filter(|x| x > 1) |> map(|x| x * x) |> reduce(|a, b| a + b, 0)", "29"},
+		{"[1, 2, 3].map(|x| x + 1).filter(|x| x > 2)", "[3, 4]"},
+		{"range(3).map(|i| i * 10)", "[0, 10, 20]"},
+		{"let gen = fn() { yield 1; yield 2 }; gen().map(|x| -x)", "[-1, -2]"},
+		{"let k = 3; map([1, 2], |x| x * k)", "[3, 6]"},
+		{"let add = |a, b| a + b; 1 |> add(2)", "3"},
+		{"let twice = |f| |x| f(f(x)); 5 |> twice(|x| x * 3)()", "45"},
+		{"let answer = || 42; answer()", "42"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMapFilterReduceErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map(5, |x| x)", "first argument to `map` must be iterable, got INTEGER"},
+		{"filter([1], 2)", "second argument to `filter` must be FUNCTION, got INTEGER"},
+		{"reduce([1], |a, b| a + b)", "wrong number of arguments. got=2, want=3"},
+		{"map([1, true], |x| -x)", "unknown operator: -BOOLEAN"},
+		{"map([1], |a, b| a)", "wrong number of arguments. got=1, want=2"},
+		{"1 |> 2", "not a function: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+// 宿主程序中实现object.Iterable的类型
+type countdown struct{ from int64 }
+
+func (c *countdown) Type() object.ObjectType { return "COUNTDOWN" }
+func (c *countdown) Inspect() string         { return "countdown" }
+func (c *countdown) Iter() object.Iterator   { return &countdownIterator{n: c.from} }
+
+type countdownIterator struct{ n int64 }
+
+func (it *countdownIterator) Next() (object.Object, object.Object, bool) {
+	if it.n == 0 {
+		return nil, nil, false
+	}
+	it.n--
+	return NULL, &object.Integer{Value: it.n + 1}, true
+}
+
+func TestForExpressionOverHostIterable(t *testing.T) {
+	program := testParseProgram("for (n in c) { n * 10 }")
+	env := object.NewEnvironment()
+	env.Set("c", &countdown{from: 3})
+
+	evaluated := Eval(program, env)
+	if evaluated.Inspect() != "[30, 20, 10]" {
+		t.Errorf("wrong result. got=%q", evaluated.Inspect())
+	}
+}
@@ -0,0 +1,170 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"monkey/object"
+	"strings"
+)
+
+// json_parse(str)
+// 将JSON文本解析为Monkey对象，对象键按字符串处理
+func jsonParse(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `json_parse` must be STRING, got %s",
+			args[0].Type())
+	}
+
+	dec := json.NewDecoder(strings.NewReader(str.Value))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return newError("invalid JSON: %s", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return newError("invalid JSON: unexpected data after top-level value")
+	}
+
+	return jsonToObject(value)
+}
+
+// json_stringify整数缩进的最大空格数
+const maxJSONIndent = 10
+
+// json_stringify(obj[, indent]) 或 json_stringify(obj, indent: n)
+// indent为整数（空格数）或字符串时输出缩进格式，键按字典序排列
+func jsonStringify(args ...object.Object) object.Object {
+	args, options := splitOptions(args)
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+
+	indentArg := optionValue(options, "indent")
+	if len(args) == 2 {
+		if indentArg != nil {
+			return newErrorOfKind(TYPE_ERROR, "multiple values for argument `indent`")
+		}
+		indentArg = args[1]
+	}
+
+	indent := ""
+	switch arg := indentArg.(type) {
+	case nil:
+	case *object.Integer:
+		if arg.Value < 0 || arg.Value > maxJSONIndent {
+			return newError("indent to `json_stringify` must be between 0 and %d, got %d",
+				maxJSONIndent, arg.Value)
+		}
+		indent = strings.Repeat(" ", int(arg.Value))
+	case *object.String:
+		indent = arg.Value
+	default:
+		return newError("indent to `json_stringify` must be INTEGER or STRING, got %s",
+			indentArg.Type())
+	}
+
+	value, errObj := objectToJSON(args[0])
+	if errObj != nil {
+		return errObj
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(value); err != nil {
+		return newError("json_stringify: %s", err)
+	}
+
+	return &object.String{Value: strings.TrimSuffix(out.String(), "\n")}
+}
+
+// JSON值转换为Monkey对象
+func jsonToObject(value interface{}) object.Object {
+	switch value := value.(type) {
+	case nil:
+		return NULL
+	case bool:
+		return nativeBoolToBooleanObject(value)
+	case string:
+		return &object.String{Value: value}
+	case json.Number:
+		i, err := value.Int64()
+		if err != nil {
+			return newError("invalid JSON: number %s is not an integer", value)
+		}
+		return &object.Integer{Value: i}
+	case []interface{}:
+		elements := make([]object.Object, 0, len(value))
+		for _, v := range value {
+			obj := jsonToObject(v)
+			if isError(obj) {
+				return obj
+			}
+			elements = append(elements, obj)
+		}
+		return &object.Array{Elements: elements}
+	case map[string]interface{}:
+		pairs := make(map[object.HashKey]object.HashPair)
+		for k, v := range value {
+			obj := jsonToObject(v)
+			if isError(obj) {
+				return obj
+			}
+			key := &object.String{Value: k}
+			pairs[key.HashKey()] = object.HashPair{Key: key, Value: obj}
+		}
+		return &object.Hash{Pairs: pairs}
+	default:
+		return newError("invalid JSON: unsupported value %v", value)
+	}
+}
+
+// Monkey对象转换为可编码的JSON值
+// map[string]interface{}在编码时按键排序，保证输出稳定
+func objectToJSON(obj object.Object) (interface{}, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Null:
+		return nil, nil
+	case *object.Boolean:
+		return obj.Value, nil
+	case *object.Integer:
+		return obj.Value, nil
+	case *object.String:
+		return obj.Value, nil
+	case *object.Array:
+		values := make([]interface{}, 0, len(obj.Elements))
+		for _, e := range obj.Elements {
+			v, err := objectToJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case *object.Hash:
+		values := make(map[string]interface{}, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return nil, newError("unusable as JSON object key: %s", pair.Key.Type())
+			}
+			v, err := objectToJSON(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			values[key.Value] = v
+		}
+		return values, nil
+	default:
+		return nil, newError("value not supported by `json_stringify`: %s", obj.Type())
+	}
+}
@@ -0,0 +1,118 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestJSONStringify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_stringify(1)`, `1`},
+		{`json_stringify("monkey")`, `"monkey"`},
+		{`json_stringify(true)`, `true`},
+		{`json_stringify(first([]))`, `null`},
+		{`json_stringify([1, "two", [3]])`, `[1,"two",[3]]`},
+		{`json_stringify({"b": 2, "a": 1, "c": {"z": 0, "y": [true]}})`,
+			`{"a":1,"b":2,"c":{"y":[true],"z":0}}`},
+		{`json_stringify({"b": [1, 2], "a": 1}, 2)`,
+			"{\n  \"a\": 1,\n  \"b\": [\n    1,\n    2\n  ]\n}"},
+		{`json_stringify([1], "--")`, "[\n--1\n]"},
+		{`json_stringify([1], indent: "--")`, "[\n--1\n]"},
+		{`json_stringify(json_parse(json_stringify({"a": [1, {"b": false}]})))`,
+			`{"a":[1,{"b":false}]}`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("wrong JSON for %s. want=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+}
+
+func TestJSONParse(t *testing.T) {
+	input := `{"name": "Monkey", "age": 7, "tags": ["a", "b"], "ok": true, "none": null}`
+
+	evaluated := jsonParse(&object.String{Value: input})
+	hash, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("object is not Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	get := func(key string) object.Object {
+		pair, ok := hash.Pairs[(&object.String{Value: key}).HashKey()]
+		if !ok {
+			t.Fatalf("no pair for key %q", key)
+		}
+		return pair.Value
+	}
+
+	if name, ok := get("name").(*object.String); !ok || name.Value != "Monkey" {
+		t.Errorf("wrong name. got=%+v", get("name"))
+	}
+	testIntegerObject(t, get("age"), 7)
+	testBooleanObject(t, get("ok"), true)
+	testNullObject(t, get("none"))
+
+	tags, ok := get("tags").(*object.Array)
+	if !ok || len(tags.Elements) != 2 {
+		t.Fatalf("wrong tags. got=%+v", get("tags"))
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    object.Object
+		expected string
+	}{
+		{&object.String{Value: `{"a": `}, "invalid JSON: unexpected EOF"},
+		{&object.String{Value: `[1] [2]`}, "invalid JSON: unexpected data after top-level value"},
+		{&object.String{Value: `1.5`}, "invalid JSON: number 1.5 is not an integer"},
+		{&object.Integer{Value: 1}, "argument to `json_parse` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := jsonParse(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %s", tt.input.Inspect())
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expected, errObj.Message)
+		}
+	}
+
+	stringifyTests := []struct {
+		input    string
+		expected string
+	}{
+		{`json_stringify({1: 2})`, "unusable as JSON object key: INTEGER"},
+		{`json_stringify([fn(x) { x }])`, "value not supported by `json_stringify`: FUNCTION"},
+		{`json_stringify(1, true)`, "indent to `json_stringify` must be INTEGER or STRING, got BOOLEAN"},
+		{`json_stringify([1], -1)`, "indent to `json_stringify` must be between 0 and 10, got -1"},
+		{`json_stringify([1], indent: 1000000000000)`, "indent to `json_stringify` must be between 0 and 10, got 1000000000000"},
+		{`json_stringify(1, 2, indent: 2)`, "multiple values for argument `indent`"},
+		{`json_stringify(1, sort: true)`, "unknown keyword argument `sort`"},
+	}
+
+	for _, tt := range stringifyTests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %s", tt.input)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expected, errObj.Message)
+		}
+	}
+}
@@ -22,9 +22,10 @@ func DefineMacros(program *ast.Program, env *object.Environment) {
 
 func isMacroDefinition(node ast.Statement) bool {
 	letStatement, ok := node.(*ast.LetStatement)
-	if ok {
-		_, ok = letStatement.Value.(*ast.MacroLiteral)
+	if !ok || letStatement.Name == nil {
+		return false
 	}
+	_, ok = letStatement.Value.(*ast.MacroLiteral)
 	return ok
 }
 
@@ -45,7 +46,7 @@ func ExpandMacros(program ast.Node, env *object.Environment) ast.Node {
 			if macro, ok := isMacroCall(callExpression, env); ok {
 				args := quoteArgs(callExpression)
 				evalEnv := extendMacroEnv(macro, args)
-				evaluated := Eval(macro.Body, evalEnv)
+				evaluated := evalBlockStatement(macro.Body, evalEnv)
 				if quote, ok := evaluated.(*object.Quote); ok {
 					return quote.Node
 				}
@@ -98,6 +98,26 @@ func TestExpandMacros(t *testing.T) {
 			unless(10>5, puts("greater"), puts("not greater"));`,
 			`if(10 > 5) { puts("greater") } else { puts("not greater") }`,
 		},
+		{
+			`let sign = macro(x) {
+				quote(if (unquote(x) > 0) { 1 } else if (unquote(x) < 0) { -1 } else { 0 });
+			};
+			sign(a - b);`,
+			`if ((a - b) > 0) { 1 } else if ((a - b) < 0) { -1 } else { 0 }`,
+		},
+		{
+			`let oneOf = macro(x, a, b) {
+				quote(switch (unquote(x)) { case unquote(a), unquote(b): true default: false });
+			};
+			oneOf(n + 1, 2 * 3, 7);`,
+			`switch (n + 1) { case 2 * 3, 7: true default: false }`,
+		},
+		{
+			`let inc = macro(a) { quote(unquote(a) + 1) };
+			inc(x);
+			inc(y);`,
+			`(x + 1); (y + 1)`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -113,3 +133,47 @@ func TestExpandMacros(t *testing.T) {
 		}
 	}
 }
+
+func TestHygienicMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{
+			// 宏引入的tmp遮蔽了调用处的tmp
+			`let with = macro(value, body) {
+				quote(if (true) { let tmp = unquote(value); unquote(body) + tmp });
+			};
+			let tmp = 10;
+			with(1, tmp);`,
+			2,
+		},
+		{
+			`let with = macro(value, body) {
+				let tmp = gensym("tmp");
+				quote(if (true) { let unquote(tmp) = unquote(value); unquote(body) + unquote(tmp) });
+			};
+			let tmp = 10;
+			with(1, tmp);`,
+			11,
+		},
+		{
+			`let twice = macro(x) {
+				let v = gensym();
+				quote(if (true) { let unquote(v) = unquote(x); unquote(v) * 2 });
+			};
+			let v = 3;
+			twice(v + 1) + twice(v);`,
+			14,
+		},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded := ExpandMacros(program, env)
+
+		testIntegerObject(t, Eval(expanded, object.NewEnvironment()), tt.expected)
+	}
+}
@@ -0,0 +1,259 @@
+package evaluator
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 求值match表达式
+// 依次尝试每个分支，模式匹配且守卫条件成立时求值分支表达式；
+// 模式中的绑定只在该分支内可见
+func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range me.Arms {
+		bindings := make(map[string]object.Object)
+		ok, _, err := matchPattern(arm.Pattern, subject, env, bindings)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		armEnv := object.NewEnclosedEnvironment(env)
+		for name, val := range bindings {
+			armEnv.Set(name, val)
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return newError("no pattern matched value: %s", subject.Inspect())
+}
+
+// 解构let语句，值与模式不匹配时返回错误，不产生任何绑定
+func evalDestructuring(pattern ast.Expression, val object.Object, env *object.Environment, constant bool) object.Object {
+	bindings := make(map[string]object.Object)
+	ok, reason, err := matchPattern(pattern, val, env, bindings)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return newErrorOfKind(TYPE_ERROR, "cannot destructure %s: %s", val.Type(), reason)
+	}
+
+	for name := range bindings {
+		if env.IsConst(name) {
+			return newErrorOfKind(TYPE_ERROR, "cannot reassign constant %s", name)
+		}
+	}
+	for name, v := range bindings {
+		bind(env, name, v, constant)
+	}
+
+	return nil
+}
+
+// 将值与模式匹配，模式中的绑定写入bindings，枚举变体模式在env中查找枚举
+// 不匹配时返回原因，模式本身有误（如引用不存在的变体）时返回错误；
+// 此时bindings中可能留有部分绑定，调用方应丢弃
+func matchPattern(pattern ast.Expression, val object.Object, env *object.Environment, bindings map[string]object.Object) (bool, string, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if name := pattern.Token.Literal; name != "_" {
+			bindings[name] = val
+		}
+		return true, "", nil
+
+	case *ast.IntegerLiteral, *ast.StringLiteral, *ast.Boolean, *ast.NullLiteral, *ast.PrefixExpression:
+		// 字面量模式不引用任何变量，无需环境
+		expected := Eval(pattern, nil)
+		if !objectsEqual(expected, val) {
+			return false, fmt.Sprintf("expected %s, got %s", expected.Inspect(), val.Inspect()), nil
+		}
+		return true, "", nil
+
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, val, env, bindings)
+
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, val, env, bindings)
+
+	case *ast.VariantPattern:
+		return matchVariantPattern(pattern, val, env, bindings)
+
+	default:
+		return false, fmt.Sprintf("unsupported pattern: %s", pattern.String()), nil
+	}
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, val object.Object, env *object.Environment, bindings map[string]object.Object) (bool, string, object.Object) {
+	array, ok := val.(*object.Array)
+	if !ok {
+		return false, fmt.Sprintf("expected ARRAY, got %s", val.Type()), nil
+	}
+
+	want, got := len(pattern.Elements), len(array.Elements)
+	if pattern.Rest == nil && got != want {
+		return false, fmt.Sprintf("expected %d elements, got %d", want, got), nil
+	}
+	if pattern.Rest != nil && got < want {
+		return false, fmt.Sprintf("expected at least %d elements, got %d", want, got), nil
+	}
+
+	for i, element := range pattern.Elements {
+		if ok, reason, err := matchPattern(element, array.Elements[i], env, bindings); !ok || err != nil {
+			return false, reason, err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, got-want)
+		copy(rest, array.Elements[want:])
+		matchPattern(pattern.Rest, &object.Array{Elements: rest}, env, bindings)
+	}
+
+	return true, "", nil
+}
+
+func matchHashPattern(pattern *ast.HashPattern, val object.Object, env *object.Environment, bindings map[string]object.Object) (bool, string, object.Object) {
+	hash, ok := val.(*object.Hash)
+	if !ok {
+		return false, fmt.Sprintf("expected HASH, got %s", val.Type()), nil
+	}
+
+	for i, keyNode := range pattern.Keys {
+		key, ok := Eval(keyNode, nil).(object.Hashable)
+		if !ok {
+			return false, fmt.Sprintf("unusable as hash key: %s", keyNode.String()), nil
+		}
+
+		pair, ok := hash.Pairs[key.HashKey()]
+		if !ok {
+			return false, fmt.Sprintf("missing key %s", keyNode.String()), nil
+		}
+
+		if ok, reason, err := matchPattern(pattern.Values[i], pair.Value, env, bindings); !ok || err != nil {
+			return false, reason, err
+		}
+	}
+
+	return true, "", nil
+}
+
+// 枚举变体模式在环境中查找枚举，按变体本身匹配，同名的另一个枚举的值不匹配
+func matchVariantPattern(pattern *ast.VariantPattern, val object.Object, env *object.Environment, bindings map[string]object.Object) (bool, string, object.Object) {
+	variant, err := resolveVariantPattern(pattern, env)
+	if err != nil {
+		return false, "", err
+	}
+
+	value, ok := val.(*object.EnumValue)
+	if !ok {
+		return false, fmt.Sprintf("expected ENUM_VALUE, got %s", val.Type()), nil
+	}
+	if value.Variant != variant {
+		return false, fmt.Sprintf("expected %s, got %s", pattern.String(), value.Inspect()), nil
+	}
+
+	for i, field := range pattern.Fields {
+		if ok, reason, err := matchPattern(field, value.Values[i], env, bindings); !ok || err != nil {
+			return false, reason, err
+		}
+	}
+
+	return true, "", nil
+}
+
+// 查找变体模式引用的变体
+// 枚举不存在、变体不存在或模式的字段数与变体不符时返回错误；不带括号的模式不检查字段
+func resolveVariantPattern(pattern *ast.VariantPattern, env *object.Environment) (*object.Variant, object.Object) {
+	name := pattern.Enum.Token.Literal
+	obj, ok := env.Get(name)
+	if !ok {
+		return nil, withPosition(newError("unknown enum `%s` in pattern %s", name, pattern.String()), pattern.Enum.Token)
+	}
+	enum, ok := obj.(*object.Enum)
+	if !ok {
+		return nil, withPosition(newError("`%s` in pattern %s is not an enum, got %s", name, pattern.String(), obj.Type()), pattern.Enum.Token)
+	}
+
+	variant := enum.Variant(pattern.Variant.Token.Literal)
+	if variant == nil {
+		return nil, withPosition(newError("`%s` has no variant `%s`", name, pattern.Variant.Token.Literal), pattern.Variant.Token)
+	}
+	if pattern.Fields != nil && len(pattern.Fields) != len(variant.Fields) {
+		return nil, withPosition(newError("wrong number of fields in pattern %s. got=%d, want=%d",
+			pattern.String(), len(pattern.Fields), len(variant.Fields)), pattern.Enum.Token)
+	}
+
+	return variant, nil
+}
+
+// 判断两个值是否相等
+// 整数、字符串、布尔值按值比较，数组、哈希和枚举值逐个元素比较，其他对象比较是否为同一对象
+func objectsEqual(a, b object.Object) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a := a.(type) {
+	case *object.Integer:
+		return a.Value == b.(*object.Integer).Value
+	case *object.String:
+		return a.Value == b.(*object.String).Value
+	case *object.Boolean:
+		return a.Value == b.(*object.Boolean).Value
+	case *object.Array:
+		b := b.(*object.Array)
+		if len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !objectsEqual(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *object.Hash:
+		b := b.(*object.Hash)
+		if len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+		for key, pair := range a.Pairs {
+			other, ok := b.Pairs[key]
+			if !ok || !objectsEqual(pair.Value, other.Value) {
+				return false
+			}
+		}
+		return true
+	case *object.EnumValue:
+		b := b.(*object.EnumValue)
+		if a.Variant != b.Variant {
+			return false
+		}
+		for i := range a.Values {
+			if !objectsEqual(a.Values[i], b.Values[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
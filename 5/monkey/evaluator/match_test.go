@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`match 1 { 1 => "one", 2 => "two" }`, "one"},
+		{`match 2 { 1 => "one", 2 => "two" }`, "two"},
+		{`match -3 { -3 => "minus three", _ => "other" }`, "minus three"},
+		{`match "b" { "a" => 1, "b" => 2, }`, 2},
+		{`match true { false => 0, true => 1 }`, 1},
+		{`match 42 { _ => "anything" }`, "anything"},
+		{`match 5 { n => n * 2 }`, 10},
+		{`match [1, 2] { [a, b] => a + b }`, 3},
+		{`match [1, 2, 3] { [a, b] => "two", [a, b, c] => "three" }`, "three"},
+		{`match [1, 2, 3, 4] { [first, ...rest] => len(rest) }`, 3},
+		{`match [1] { [a, b, ...rest] => "long", [a, ..._] => "short" }`, "short"},
+		{`match [] { [] => "empty", _ => "other" }`, "empty"},
+		{`match [1, [2, 3]] { [a, [b, c]] => a + b + c }`, 6},
+		{`match [0, 5] { [0, x] => x, [y, x] => y }`, 5},
+		{`match {"name": "Monkey", "age": 7} { {"name": name, age} => name }`, "Monkey"},
+		{`match {"age": 7} { {"name": name} => name, {age} => age }`, 7},
+		{`match {"kind": "circle", "r": 2} { {kind: "square", side} => side, {kind: "circle", r} => r * 3 }`, 6},
+		{`match {1: "one"} { {1: v} => v }`, "one"},
+		{`match 7 { n if n > 10 => "big", n if n > 5 => "medium", _ => "small" }`, "medium"},
+		{`match [3, 1] { [a, b] if a < b => "asc", [a, b] => "desc" }`, "desc"},
+		{`let x = 1; match 2 { x => x }; x`, 1},
+		{`match "str" { 1 => "int", [a] => "array", {a} => "hash", _ => "other" }`, "other"},
+		{`match 3 { 1 => "one" }`, "no pattern matched value: 3"},
+		{`match 3 { n if n + true => 1 }`, "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected && evaluated.Inspect() != "ERROR: "+expected {
+				t.Errorf("wrong result for %s. want=%q, got=%q",
+					tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let [a, b] = [1, 2]; a + b", 3},
+		{"let [first, ...rest] = [1, 2, 3]; len(rest) * 10 + first", 21},
+		{"let [x, ..._] = [7, 8, 9]; x", 7},
+		{"let [[a, b], c] = [[1, 2], 3]; a + b + c", 6},
+		{`let {name, age} = {"name": "Monkey", "age": 7}; age`, 7},
+		{`let {"first name": first} = {"first name": "Thorsten"}; first`, "Thorsten"},
+		{`let {items: [head, ...tail]} = {"items": [4, 5]}; head + len(tail)`, 5},
+		{`let divmod = fn(a, b) { [a / b, a - a / b * b] }; let [q, r] = divmod(17, 5); q * 10 + r`, 32},
+		{"let [a, b] = [1];", "cannot destructure ARRAY: expected 2 elements, got 1"},
+		{"let [a, b, ...c] = [1];", "cannot destructure ARRAY: expected at least 2 elements, got 1"},
+		{"let [a] = 5;", "cannot destructure INTEGER: expected ARRAY, got INTEGER"},
+		{`let {name} = {"age": 7};`, "cannot destructure HASH: missing key name"},
+		{`let {name} = [1];`, "cannot destructure ARRAY: expected HASH, got ARRAY"},
+		{"let [a, 1] = [5, 2]; a", "cannot destructure ARRAY: expected 1, got 2"},
+		{"let a = 1; let [a, 1] = [5, 2]; a", "cannot destructure ARRAY: expected 1, got 2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected && evaluated.Inspect() != "ERROR: "+expected {
+				t.Errorf("wrong result for %s. want=%q, got=%q",
+					tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+
+	// 解构失败时不应产生部分绑定
+	env := object.NewEnvironment()
+	Eval(testParseProgram("let [a, 1] = [5, 2];"), env)
+	if _, ok := env.Get("a"); ok {
+		t.Errorf("failed destructuring left a binding for a")
+	}
+}
@@ -0,0 +1,288 @@
+package evaluator
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"monkey/object"
+	"sync"
+	"time"
+)
+
+// 随机数生成器，通过seed(n)或SeedRandom设置种子后结果可复现
+var random = struct {
+	sync.Mutex
+	rand *rand.Rand
+}{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// 设置随机数种子
+func SeedRandom(seed int64) {
+	random.Lock()
+	defer random.Unlock()
+	random.rand = rand.New(rand.NewSource(seed))
+}
+
+// 检查参数都是INTEGER，返回对应的整数值
+func integerArgs(name string, args []object.Object) ([]int64, *object.Error) {
+	values := make([]int64, len(args))
+	for i, arg := range args {
+		integer, ok := arg.(*object.Integer)
+		if !ok {
+			return nil, newError("argument to `%s` must be INTEGER, got %s",
+				name, arg.Type())
+		}
+		values[i] = integer.Value
+	}
+	return values, nil
+}
+
+// abs(n)
+func mathAbs(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	values, errObj := integerArgs("abs", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	if values[0] == math.MinInt64 {
+		return newError("integer overflow in `abs`")
+	}
+	if values[0] < 0 {
+		return &object.Integer{Value: -values[0]}
+	}
+	return args[0]
+}
+
+// 取得min/max的候选值，可以是多个整数，也可以是一个整数数组
+func extremumArgs(name string, args []object.Object) ([]int64, *object.Error) {
+	if len(args) == 1 {
+		if arr, ok := args[0].(*object.Array); ok {
+			args = arr.Elements
+		}
+	}
+	if len(args) == 0 {
+		return nil, newError("`%s` needs at least one value", name)
+	}
+	return integerArgs(name, args)
+}
+
+// min(a, b, ...) 或 min(array)
+func mathMin(args ...object.Object) object.Object {
+	values, errObj := extremumArgs("min", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return &object.Integer{Value: result}
+}
+
+// max(a, b, ...) 或 max(array)
+func mathMax(args ...object.Object) object.Object {
+	values, errObj := extremumArgs("max", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	result := values[0]
+	for _, v := range values[1:] {
+		if v > result {
+			result = v
+		}
+	}
+	return &object.Integer{Value: result}
+}
+
+// pow(base, exp)
+// 整数幂，exp不能为负数，溢出时返回错误
+func mathPow(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	values, errObj := integerArgs("pow", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	base, exp := values[0], values[1]
+	if exp < 0 {
+		return newError("negative exponent to `pow`: %d", exp)
+	}
+
+	// 在绝对值上按平方求幂，结果为负时允许绝对值达到2^63（即MinInt64）
+	// 还需要继续平方时，底数的平方超过上限说明结果也会超过上限
+	negative := base < 0 && exp%2 == 1
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++
+	}
+
+	result, square := uint64(1), abs64(base)
+	for {
+		if exp%2 == 1 {
+			hi, lo := bits.Mul64(result, square)
+			if hi != 0 || lo > limit {
+				return newError("integer overflow in `pow`")
+			}
+			result = lo
+		}
+		exp /= 2
+		if exp == 0 {
+			break
+		}
+		hi, lo := bits.Mul64(square, square)
+		if hi != 0 || lo > limit {
+			return newError("integer overflow in `pow`")
+		}
+		square = lo
+	}
+	if negative {
+		return &object.Integer{Value: int64(-result)}
+	}
+	return &object.Integer{Value: int64(result)}
+}
+
+// 整数的绝对值，MinInt64的绝对值2^63也能表示
+func abs64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-n)
+	}
+	return uint64(n)
+}
+
+// sqrt(n)
+// 整数平方根（向下取整）
+func mathSqrt(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	values, errObj := integerArgs("sqrt", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	n := values[0]
+	if n < 0 {
+		return newError("negative argument to `sqrt`: %d", n)
+	}
+
+	// 先用浮点数近似，再修正舍入误差（用除法比较避免溢出）
+	x := int64(math.Sqrt(float64(n)))
+	for x > 0 && x > n/x {
+		x--
+	}
+	for x+1 <= n/(x+1) {
+		x++
+	}
+	return &object.Integer{Value: x}
+}
+
+// seed(n)
+func mathSeed(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	values, errObj := integerArgs("seed", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	SeedRandom(values[0])
+	return NULL
+}
+
+// random() 或 random(n)
+// 无参数时返回非负随机整数，否则返回[0, n)内的随机整数
+func mathRandom(args ...object.Object) object.Object {
+	if len(args) > 1 {
+		return newError("wrong number of arguments. got=%d, want=0 or 1",
+			len(args))
+	}
+	values, errObj := integerArgs("random", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	random.Lock()
+	defer random.Unlock()
+
+	if len(values) == 0 {
+		return &object.Integer{Value: random.rand.Int63()}
+	}
+	if values[0] <= 0 {
+		return newError("argument to `random` must be positive, got %d", values[0])
+	}
+	return &object.Integer{Value: random.rand.Int63n(values[0])}
+}
+
+// random_int(lo, hi)
+// 返回[lo, hi]内的随机整数
+func mathRandomInt(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	values, errObj := integerArgs("random_int", args)
+	if errObj != nil {
+		return errObj
+	}
+
+	lo, hi := values[0], values[1]
+	if lo > hi {
+		return newError("empty range to `random_int`: %d > %d", lo, hi)
+	}
+
+	random.Lock()
+	defer random.Unlock()
+	// 用无符号数表示区间跨度，random_int(MinInt64, MaxInt64)也不会溢出
+	n := randomUint64n(random.rand, uint64(hi)-uint64(lo))
+	return &object.Integer{Value: int64(uint64(lo) + n)}
+}
+
+// 返回[0, n]内均匀分布的随机数，调用方需持有random的锁
+func randomUint64n(r *rand.Rand, n uint64) uint64 {
+	if n < math.MaxInt64 {
+		return uint64(r.Int63n(int64(n + 1)))
+	}
+	for {
+		if v := r.Uint64(); v <= n {
+			return v
+		}
+	}
+}
+
+// shuffle(array)
+// 返回打乱顺序后的新数组
+func mathShuffle(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newError("argument to `shuffle` must be ARRAY, got %s",
+			args[0].Type())
+	}
+
+	elements := make([]object.Object, len(arr.Elements))
+	copy(elements, arr.Elements)
+
+	random.Lock()
+	defer random.Unlock()
+	random.rand.Shuffle(len(elements), func(i, j int) {
+		elements[i], elements[j] = elements[j], elements[i]
+	})
+
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,96 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`abs(-5)`, 5},
+		{`abs(5)`, 5},
+		{`min(3, -1, 2)`, -1},
+		{`min([4, 8])`, 4},
+		{`max(3, -1, 2)`, 3},
+		{`max([4, 8])`, 8},
+		{`pow(2, 10)`, 1024},
+		{`pow(-3, 3)`, -27},
+		{`pow(7, 0)`, 1},
+		{`pow(-2, 63)`, -9223372036854775808},
+		{`pow(-2, 62)`, 4611686018427387904},
+		{`pow(3, 39)`, 4052555153018976267},
+		{`pow(-9223372036854775807 - 1, 1)`, -9223372036854775808},
+		{`pow(1, 9223372036854775807)`, 1},
+		{`pow(0, 9223372036854775807)`, 0},
+		{`pow(0, 0)`, 1},
+		{`pow(-1, 9223372036854775807)`, -1},
+		{`pow(-1, 9223372036854775806)`, 1},
+		{`sqrt(0)`, 0},
+		{`sqrt(1)`, 1},
+		{`sqrt(15)`, 3},
+		{`sqrt(16)`, 4},
+		{`sqrt(9223372036854775807)`, 3037000499},
+		{`abs("x")`, "argument to `abs` must be INTEGER, got STRING"},
+		{`min()`, "`min` needs at least one value"},
+		{`max([1, "two"])`, "argument to `max` must be INTEGER, got STRING"},
+		{`pow(2, -1)`, "negative exponent to `pow`: -1"},
+		{`pow(2, 63)`, "integer overflow in `pow`"},
+		{`pow(-2, 64)`, "integer overflow in `pow`"},
+		{`pow(3, 40)`, "integer overflow in `pow`"},
+		{`pow(2, 9223372036854775807)`, "integer overflow in `pow`"},
+		{`pow(-9223372036854775807 - 1, 2)`, "integer overflow in `pow`"},
+		{`abs(-9223372036854775807 - 1)`, "integer overflow in `abs`"},
+		{`sqrt(-4)`, "negative argument to `sqrt`: -4"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestSeededRandom(t *testing.T) {
+	input := `seed(42);
+[random(), random(10), random_int(5, 7), shuffle([1, 2, 3, 4, 5, 6])]`
+
+	first := testEval(input).Inspect()
+	second := testEval(input).Inspect()
+	if first != second {
+		t.Errorf("seeded results differ. first=%s, second=%s", first, second)
+	}
+
+	for i := 0; i < 100; i++ {
+		n, ok := testEval(`random_int(-2, 2)`).(*object.Integer)
+		if !ok || n.Value < -2 || n.Value > 2 {
+			t.Fatalf("random_int out of range. got=%+v", n)
+		}
+	}
+
+	full, ok := testEval(`random_int(-9223372036854775807 - 1, 9223372036854775807)`).(*object.Integer)
+	if !ok {
+		t.Errorf("random_int over the full range did not return INTEGER. got=%+v", full)
+	}
+	testBooleanObject(t, testEval(`random_int(0, 9223372036854775807) > -1`), true)
+
+	testIntegerObject(t, testEval(`len(shuffle([1, 2, 3]))`), 3)
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`random(0)`, "argument to `random` must be positive, got 0"},
+		{`random_int(3, 1)`, "empty range to `random_int`: 3 > 1"},
+		{`shuffle(1)`, "argument to `shuffle` must be ARRAY, got INTEGER"},
+	}
+	for _, tt := range errors {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
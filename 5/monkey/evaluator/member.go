@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 可以用方法调用语法调用的内置函数，按接收者类型分组
+// value.name(args)等价于name(value, args)
+var builtinMethods = map[object.ObjectType][]string{
+	object.ARRAY_OBJ:     {"len", "first", "last", "rest", "push", "min", "max", "shuffle", "map", "filter", "reduce", "json_stringify"},
+	object.STRING_OBJ:    {"len", "split", "json_parse", "regex", "parse_time", "duration"},
+	object.HASH_OBJ:      {"json_stringify"},
+	object.INTEGER_OBJ:   {"abs", "pow", "sqrt", "format_time", "format_duration"},
+	object.REGEXP_OBJ:    {"match", "find_all", "replace_regex", "split_regex"},
+	object.RANGE_OBJ:     {"map", "filter", "reduce"},
+	object.GENERATOR_OBJ: {"next", "close", "map", "filter", "reduce"},
+	object.CHANNEL_OBJ:   {"send", "recv", "close"},
+	object.TASK_OBJ:      {"join"},
+	object.PROMISE_OBJ:   {"then"},
+}
+
+// 可选链中?.或?[遇到null时的结果，沿链中的成员访问、索引和调用向外传递，在可选链节点处变为NULL
+type skippedChain struct {
+	link ast.Expression // 遇到null的可选访问
+}
+
+func (sc *skippedChain) Type() object.ObjectType { return object.NULL_OBJ }
+func (sc *skippedChain) Inspect() string         { return "null" }
+
+func isSkippedChain(obj object.Object) bool {
+	_, ok := obj.(*skippedChain)
+	return ok
+}
+
+// 求值成员访问表达式
+// 哈希上按字符串键取值，键不存在时再查找内置方法，都没有时得到null；
+// 实例上先查找字段，再查找方法，方法取出时绑定self；结构体上取出未绑定的方法；
+// 枚举上取出变体（无字段的变体直接得到其值），枚举值上读取字段；
+// 其他值上查找以该类型为第一个参数的内置函数；?.在对象为null时跳过整条可选链
+func evalMemberExpression(me *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := Eval(me.Object, env)
+	if isError(obj) || isSkippedChain(obj) {
+		return obj
+	}
+	if me.Optional && obj == NULL {
+		return &skippedChain{link: me}
+	}
+	name := me.Property.Token.Literal
+
+	switch obj := obj.(type) {
+	case *object.Hash:
+		if pair, ok := obj.Pairs[(&object.String{Value: name}).HashKey()]; ok {
+			return pair.Value
+		}
+		if method := builtinMethod(obj, name); method != nil {
+			return method
+		}
+		return NULL
+
+	case *object.Instance:
+		if value, ok := obj.Fields[name]; ok {
+			return value
+		}
+		if method, ok := obj.Struct.Methods[name]; ok {
+			return bindMethod(method, obj)
+		}
+		return withPosition(newErrorOfKind(TYPE_ERROR, "`%s` has no field or method `%s`",
+			obj.Struct.Name, name), me.Token)
+
+	case *object.Struct:
+		if method, ok := obj.Methods[name]; ok {
+			return method
+		}
+		return withPosition(newErrorOfKind(TYPE_ERROR, "`%s` has no method `%s`",
+			obj.Name, name), me.Token)
+
+	case *object.Enum:
+		variant := obj.Variant(name)
+		if variant == nil {
+			return withPosition(newErrorOfKind(TYPE_ERROR, "`%s` has no variant `%s`",
+				obj.Name, name), me.Token)
+		}
+		if variant.Unit != nil {
+			return variant.Unit
+		}
+		return variant
+
+	case *object.EnumValue:
+		if value, ok := obj.Field(name); ok {
+			return value
+		}
+		return withPosition(newErrorOfKind(TYPE_ERROR, "`%s` has no field `%s`",
+			obj.Variant.FullName(), name), me.Token)
+
+	default:
+		if method := builtinMethod(obj, name); method != nil {
+			return method
+		}
+		return withPosition(newErrorOfKind(TYPE_ERROR, "%s has no method `%s`",
+			obj.Type(), name), me.Token)
+	}
+}
+
+// 查找接收者类型的内置方法，返回以接收者为第一个实参的内置函数，不存在时返回nil
+func builtinMethod(receiver object.Object, name string) *object.Builtin {
+	for _, method := range builtinMethods[receiver.Type()] {
+		if method != name {
+			continue
+		}
+		builtin := builtins[name]
+		return &object.Builtin{
+			Fn: func(args ...object.Object) object.Object {
+				return builtin.Fn(append([]object.Object{receiver}, args...)...)
+			},
+			Keywords: builtin.Keywords,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package evaluator
+
+import "testing"
+
+func TestMemberExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {"name": "monkey", "age": 5}; h.name`, "monkey"},
+		{`{"inner": {"x": 1}}.inner.x`, "1"},
+		{`{"a": 1}.b`, "null"},
+		{`{"len": 1}.len`, "1"},
+		{`{"a": 1}.json_stringify()`, `{"a":1}`},
+		{`{"a": [1]}.json_stringify(indent: 1)`, "{\n \"a\": [\n  1\n ]\n}"},
+		{"[1, 2].push(3)", "[1, 2, 3]"},
+		{"[1, 2, 3].push(4).rest().len()", "3"},
+		{"[3, 1, 2].max()", "3"},
+		{`"a,b,c".split(",")`, "[a, b, c]"},
+		{`"abc".split("")`, "[a, b, c]"},
+		{`"abc".len()`, "3"},
+		{"-5.abs()", "-5"},
+		{"(-5).abs()", "5"},
+		{`regex("[0-9]+").find_all("a1b22")`, "[1, 22]"},
+		{"let push = [1].push; push(2)", "[1, 2]"},
+		{"let ch = channel(1); ch.send(7); ch.recv()", "7"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMemberExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1].split()", "ARRAY has no method `split`"},
+		{"true.len()", "BOOLEAN has no method `len`"},
+		{`"abc".push(1)`, "STRING has no method `push`"},
+		{`{"a": 1}.push(2)`, "not a function: NULL"},
+		{"[1].push()", "wrong number of arguments. got=1, want=2"},
+		{`"a".split(1)`, "second argument to `split` must be STRING, got INTEGER"},
+		{"missing.x", "identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestNullHandling(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null", "null"},
+		{"null == null", "true"},
+		{`{"a": 1}["b"] == null`, "true"},
+		{"first([]) ?? 0", "0"},
+		{"5 ?? 0", "5"},
+		{"false ?? true", "false"},
+		{"null ?? null ?? 3", "3"},
+		{"1 ?? missing", "1"},
+		{`let h = {"user": {"name": "ann"}}; h.user?.name`, "ann"},
+		{`let h = {}; h.user?.name`, "null"},
+		{`let h = {}; h.user?.name ?? "anonymous"`, "anonymous"},
+		{`let h = {}; h.user?.address?.city`, "null"},
+		{`let h = {"tags": ["a"]}; h.tags?[0]`, "a"},
+		{"null?[0]", "null"},
+		{`null?[missing]`, "null"},
+		{"let a = null; a?.b.c", "null"},
+		{"let a = null; a?.m()", "null"},
+		{"let a = null; a?[0].b[1](2)", "null"},
+		{"let a = null; 1 |> a?.f(2)", "null"},
+		{"let a = {\"f\": fn(x, y) { x + y }}; 1 |> a?.f(2)", "3"},
+		{"let a = null; a?.b.c ?? 5", "5"},
+		{"let a = null; [a?.b.c, 1]", "[null, 1]"},
+		{"let a = {\"b\": {\"c\": 3}}; a?.b.c", "3"},
+		{"let a = null; a?.b.c == null", "true"},
+		{"let h = {}; h.user?.address.city", "null"},
+		{"null?.len()", "null"},
+		{"let a = null; a?.b[missing]", "null"},
+		{"match null { null => 1, _ => 2 }", "1"},
+		{"match 0 { null => 1, _ => 2 }", "2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestNullHandlingErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null.x", "NULL has no method `x`"},
+		{"null[0]", "index operator not supported: NULL"},
+		{"let a = null; (a?.b).c", "NULL has no method `c`"},
+		{"let a = {}; a?.b.c", "NULL has no method `c`"},
+		{"null ?? missing", "identifier not found: missing"},
+		{"missing ?? 1", "identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
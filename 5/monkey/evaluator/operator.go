@@ -0,0 +1,55 @@
+package evaluator
+
+import "monkey/object"
+
+// 运算符对应的方法名，结构体定义这些方法后，其实例可以作为运算符的左操作数
+// a != b取a.__eq__(b)的反；a > b在a没有__gt__时改为调用b.__lt__(a)
+var operatorMethods = map[string]string{
+	"+":  "__add__",
+	"-":  "__sub__",
+	"*":  "__mul__",
+	"/":  "__div__",
+	"<":  "__lt__",
+	">":  "__gt__",
+	"==": "__eq__",
+}
+
+// 索引运算符对应的方法名，a[i]调用a.__index__(i)
+const indexMethod = "__index__"
+
+// 调用重载的运算符方法，ok为false表示操作数没有重载该运算符
+func evalOperatorMethod(operator string, left, right object.Object) (result object.Object, ok bool) {
+	switch operator {
+	case "!=":
+		result, ok = callOperatorMethod(left, operatorMethods["=="], right)
+		if !ok || isError(result) {
+			return result, ok
+		}
+		return nativeBoolToBooleanObject(!isTruthy(result)), true
+	case ">":
+		if result, ok = callOperatorMethod(left, operatorMethods[">"], right); ok {
+			return result, true
+		}
+		return callOperatorMethod(right, operatorMethods["<"], left)
+	}
+
+	name, ok := operatorMethods[operator]
+	if !ok {
+		return nil, false
+	}
+	return callOperatorMethod(left, name, right)
+}
+
+// 接收者是定义了该方法的结构体实例时，以arg为实参调用方法
+func callOperatorMethod(receiver object.Object, name string, arg object.Object) (object.Object, bool) {
+	instance, ok := receiver.(*object.Instance)
+	if !ok {
+		return nil, false
+	}
+	method, ok := instance.Struct.Methods[name]
+	if !ok {
+		return nil, false
+	}
+
+	return applyFunction(bindMethod(method, instance), []object.Object{arg}), true
+}
@@ -0,0 +1,69 @@
+package evaluator
+
+import "testing"
+
+const vectorStruct = `
+struct Vec {
+	x, y,
+	fn __add__(self, o) { Vec(self.x + o.x, self.y + o.y) },
+	fn __sub__(self, o) { Vec(self.x - o.x, self.y - o.y) },
+	fn __mul__(self, k) { Vec(self.x * k, self.y * k) },
+	fn __div__(self, k) { Vec(self.x / k, self.y / k) },
+	fn __eq__(self, o) { self.x == o.x },
+	fn __lt__(self, o) { self.x < o.x },
+	fn __index__(self, i) { if (i == 0) { self.x } else { self.y } }
+}
+struct Money {
+	cents,
+	fn __add__(self, o) { Money(self.cents + o.cents) },
+	fn __gt__(self, o) { self.cents > o.cents }
+}
+`
+
+func TestOperatorOverloading(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Vec(1, 2) + Vec(3, 4)", "Vec(x: 4, y: 6)"},
+		{"Vec(5, 5) - Vec(1, 2)", "Vec(x: 4, y: 3)"},
+		{"Vec(1, 2) * 3", "Vec(x: 3, y: 6)"},
+		{"Vec(4, 6) / 2", "Vec(x: 2, y: 3)"},
+		{"Vec(1, 2) + Vec(1, 1) * 2", "Vec(x: 3, y: 4)"},
+		{"Vec(1, 2) == Vec(1, 3)", "true"},
+		{"Vec(1, 2) != Vec(1, 3)", "false"},
+		{"Vec(1, 2) != Vec(2, 2)", "true"},
+		{"Vec(1, 0) < Vec(2, 0)", "true"},
+		{"Vec(3, 0) > Vec(2, 0)", "true"},
+		{"Vec(1, 0) > Vec(2, 0)", "false"},
+		{"Vec(7, 8)[0] + Vec(7, 8)[1]", "15"},
+		{"Money(150) + Money(250)", "Money(cents: 400)"},
+		{"Money(2) > Money(1)", "true"},
+		{"let m = Money(1); m == m", "true"},
+		{"Money(1) == Money(1)", "false"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(vectorStruct + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestOperatorOverloadingErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Money(1) - Money(1)", "unknown operator: INSTANCE - INSTANCE"},
+		{"Money(1) < Money(2)", "unknown operator: INSTANCE < INSTANCE"},
+		{"Money(1)[0]", "index operator not supported: INSTANCE"},
+		{"Vec(1, 2) + 1", "INTEGER has no method `x`"},
+		{"1 + Vec(1, 2)", "type mismatch: INTEGER + INSTANCE"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(vectorStruct+tt.input), tt.expected)
+	}
+}
@@ -1,14 +1,17 @@
 package evaluator
 
 import (
+	"fmt"
 	"monkey/ast"
 	"monkey/object"
 	"monkey/token"
 	"strconv"
+	"sync/atomic"
 )
 
+// quote拷贝节点后再替换unquote，宏多次展开时不会改写宏体本身
 func quote(node ast.Node, env *object.Environment) object.Object {
-	node = evalUnquoteCalls(node, env)
+	node = evalUnquoteCalls(ast.Copy(node), env)
 	return &object.Quote{Node: node}
 }
 
@@ -19,6 +22,12 @@ func evalUnquoteCalls(quoted ast.Node, env *object.Environment) ast.Node {
 			unquoted := Eval(call.Arguments[0], env)
 			return convertObjectToASTNode(unquoted)
 		}
+		// let unquote(name) = ...替换后绑定的是普通标识符
+		if let, ok := node.(*ast.LetStatement); ok {
+			if name, ok := let.Pattern.(*ast.Identifier); ok {
+				let.Name, let.Pattern = name, nil
+			}
+		}
 		return node
 	})
 }
@@ -49,3 +58,28 @@ func convertObjectToASTNode(obj object.Object) ast.Node {
 		return nil
 	}
 }
+
+// gensym生成的标识符计数
+var gensymCounter int64
+
+// gensym()、gensym(prefix)
+// 生成唯一的标识符，在宏中配合quote/unquote使用，避免宏引入的变量与调用处的变量冲突：
+// let tmp = gensym("tmp"); quote(let unquote(tmp) = ...)
+// 标识符名称含#，源码中的标识符不会与之重名
+func gensym(args ...object.Object) object.Object {
+	prefix := "g"
+	switch len(args) {
+	case 0:
+	case 1:
+		str, ok := args[0].(*object.String)
+		if !ok {
+			return newError("argument to `gensym` must be STRING, got %s", args[0].Type())
+		}
+		prefix = str.Value
+	default:
+		return newError("wrong number of arguments. got=%d, want=0 or 1", len(args))
+	}
+
+	name := fmt.Sprintf("%s#%d", prefix, atomic.AddInt64(&gensymCounter, 1))
+	return &object.Quote{Node: &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name}}}
+}
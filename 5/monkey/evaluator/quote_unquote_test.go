@@ -1,7 +1,9 @@
 package evaluator
 
 import (
+	"monkey/ast"
 	"monkey/object"
+	"strings"
 	"testing"
 )
 
@@ -71,3 +73,66 @@ func TestQuoteUnquote(t *testing.T) {
 		}
 	}
 }
+
+func TestGensym(t *testing.T) {
+	evaluated := testEval(`[gensym(), gensym("tmp"), gensym("tmp")]`)
+	array, ok := evaluated.(*object.Array)
+	if !ok {
+		t.Fatalf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	names := []string{}
+	for _, el := range array.Elements {
+		quote, ok := el.(*object.Quote)
+		if !ok {
+			t.Fatalf("expected *object.Quote. got=%T (%+v)", el, el)
+		}
+		ident, ok := quote.Node.(*ast.Identifier)
+		if !ok {
+			t.Fatalf("quote.Node is not ast.Identifier. got=%T", quote.Node)
+		}
+		names = append(names, ident.String())
+	}
+
+	if !strings.HasPrefix(names[0], "g#") {
+		t.Errorf("wrong default name. got=%q", names[0])
+	}
+	if !strings.HasPrefix(names[1], "tmp#") || !strings.HasPrefix(names[2], "tmp#") {
+		t.Errorf("wrong prefixed names. got=%q", names[1:])
+	}
+	if names[1] == names[2] {
+		t.Errorf("gensym returned the same name twice: %q", names[1])
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`gensym(1)`, "argument to `gensym` must be STRING, got INTEGER"},
+		{`gensym("a", "b")`, "wrong number of arguments. got=2, want=0 or 1"},
+		{`let unquote(x) = 1`, "cannot destructure INTEGER: unsupported pattern: unquote(x)"},
+	}
+	for _, tt := range errors {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestQuoteLetUnquote(t *testing.T) {
+	evaluated := testEval(`let name = quote(total); quote(if (true) { let unquote(name) = 1 })`)
+	quote, ok := evaluated.(*object.Quote)
+	if !ok {
+		t.Fatalf("expected *object.Quote. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	ifExpression, ok := quote.Node.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("quote.Node is not ast.IfExpression. got=%T", quote.Node)
+	}
+	let, ok := ifExpression.Consequence.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("statement is not ast.LetStatement. got=%T", ifExpression.Consequence.Statements[0])
+	}
+	if let.Pattern != nil || let.Name == nil || let.Name.String() != "total" {
+		t.Errorf("let binds wrong name. got=%q", let.String())
+	}
+}
@@ -0,0 +1,160 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"regexp"
+	"sync"
+)
+
+// 字符串模式编译结果缓存，避免在循环中重复编译
+const regexpCacheSize = 256
+
+var regexpCache = struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}{patterns: make(map[string]*regexp.Regexp)}
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	regexpCache.Lock()
+	defer regexpCache.Unlock()
+
+	if re, ok := regexpCache.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regexpCache.patterns) >= regexpCacheSize {
+		regexpCache.patterns = make(map[string]*regexp.Regexp)
+	}
+	regexpCache.patterns[pattern] = re
+
+	return re, nil
+}
+
+// 取得正则对象，模式可以是REGEXP对象或字符串
+func toRegexp(name string, pattern object.Object) (*regexp.Regexp, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *object.Regexp:
+		return pattern.Value, nil
+	case *object.String:
+		re, err := compileRegexp(pattern.Value)
+		if err != nil {
+			return nil, newError("invalid regex: %s", err)
+		}
+		return re, nil
+	default:
+		return nil, newError("pattern to `%s` must be REGEXP or STRING, got %s",
+			name, pattern.Type())
+	}
+}
+
+// 检查参数个数，并取得正则对象和待匹配字符串
+func regexpArgs(name string, want int, args []object.Object) (*regexp.Regexp, string, *object.Error) {
+	if len(args) != want {
+		return nil, "", newError("wrong number of arguments. got=%d, want=%d",
+			len(args), want)
+	}
+
+	re, errObj := toRegexp(name, args[0])
+	if errObj != nil {
+		return nil, "", errObj
+	}
+
+	str, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", newError("second argument to `%s` must be STRING, got %s",
+			name, args[1].Type())
+	}
+
+	return re, str.Value, nil
+}
+
+func stringsToArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = &object.String{Value: v}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// regex(pattern)
+// 编译正则表达式，返回可重复使用的REGEXP对象
+func regexCompile(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newError("argument to `regex` must be STRING, got %s",
+			args[0].Type())
+	}
+
+	re, err := regexp.Compile(args[0].(*object.String).Value)
+	if err != nil {
+		return newError("invalid regex: %s", err)
+	}
+
+	return &object.Regexp{Value: re}
+}
+
+// match(pattern, str)
+// 返回第一个匹配及其分组组成的数组，无匹配返回null
+func regexMatch(args ...object.Object) object.Object {
+	re, str, errObj := regexpArgs("match", 2, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	groups := re.FindStringSubmatch(str)
+	if groups == nil {
+		return NULL
+	}
+
+	return stringsToArray(groups)
+}
+
+// find_all(pattern, str)
+// 返回所有匹配的字符串
+func regexFindAll(args ...object.Object) object.Object {
+	re, str, errObj := regexpArgs("find_all", 2, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	return stringsToArray(re.FindAllString(str, -1))
+}
+
+// replace_regex(pattern, str, replacement)
+// 替换所有匹配，replacement中可以使用$1、${name}引用分组
+func regexReplace(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newError("wrong number of arguments. got=%d, want=3",
+			len(args))
+	}
+	re, str, errObj := regexpArgs("replace_regex", 2, args[:2])
+	if errObj != nil {
+		return errObj
+	}
+	repl, ok := args[2].(*object.String)
+	if !ok {
+		return newError("third argument to `replace_regex` must be STRING, got %s",
+			args[2].Type())
+	}
+
+	return &object.String{Value: re.ReplaceAllString(str, repl.Value)}
+}
+
+// split_regex(pattern, str)
+// 按匹配位置切分字符串
+func regexSplit(args ...object.Object) object.Object {
+	re, str, errObj := regexpArgs("split_regex", 2, args)
+	if errObj != nil {
+		return errObj
+	}
+
+	return stringsToArray(re.Split(str, -1))
+}
@@ -0,0 +1,65 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestRegexpBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match("(\w+)@(\w+)", "mail bob@example now")`, "[bob@example, bob, example]"},
+		{`match(regex("^\d+$"), "12a")`, "null"},
+		{`regex("a+").match("baa")`, "[aa]"},
+		{`let m = match("a", "cat"); match m { [x] => x, _ => "none" }`, "a"},
+		{`if (match("z", "cat")) { 1 } else { 2 }`, "2"},
+		{`find_all("\d+", "a1 b22 c333")`, "[1, 22, 333]"},
+		{`find_all("x", "abc")`, "[]"},
+		{`replace_regex("(\w+)=(\w+)", "a=1 b=2", "$2:$1")`, "1:a 2:b"},
+		{`split_regex("\s*,\s*", "a , b,c")`, "[a, b, c]"},
+		{`let re = regex("l+"); [re, find_all(re, "hello world")]`, "[regex(l+), [ll, l]]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. want=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestRegexpErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`regex("(")`, "invalid regex: error parsing regexp: missing closing ): `(`"},
+		{`match("(", "x")`, "invalid regex: error parsing regexp: missing closing ): `(`"},
+		{`match(1, "x")`, "pattern to `match` must be REGEXP or STRING, got INTEGER"},
+		{`find_all("x", 1)`, "second argument to `find_all` must be STRING, got INTEGER"},
+		{`replace_regex("x", "x")`, "wrong number of arguments. got=2, want=3"},
+		{`replace_regex("x", "x", 1)`, "third argument to `replace_regex` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestRegexpCache(t *testing.T) {
+	first, err := compileRegexp("a+b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := compileRegexp("a+b")
+	if first != second {
+		t.Errorf("pattern was compiled twice")
+	}
+
+	if _, ok := regexCompile(&object.String{Value: "a+b"}).(*object.Regexp); !ok {
+		t.Errorf("regex did not return Regexp")
+	}
+}
@@ -0,0 +1,27 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"strings"
+)
+
+// split(str, sep)
+// 按分隔符切分字符串，sep为空字符串时切分为单个字符
+func stringSplit(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=2",
+			len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("first argument to `split` must be STRING, got %s",
+			args[0].Type())
+	}
+	sep, ok := args[1].(*object.String)
+	if !ok {
+		return newError("second argument to `split` must be STRING, got %s",
+			args[1].Type())
+	}
+
+	return stringsToArray(strings.Split(str.Value, sep.Value))
+}
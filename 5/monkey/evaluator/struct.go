@@ -0,0 +1,101 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 求值结构体声明，在当前环境中绑定结构体类型
+// 方法在声明所在的环境中闭包，可以引用结构体本身
+func evalStructStatement(ss *ast.StructStatement, env *object.Environment) object.Object {
+	s := &object.Struct{
+		Name:    ss.Name.Token.Literal,
+		Methods: make(map[string]*object.Function),
+	}
+
+	for _, f := range ss.Fields {
+		s.Fields = append(s.Fields, f.Token.Literal)
+	}
+	for _, m := range ss.Methods {
+		s.Methods[m.Name.Token.Literal] = &object.Function{
+			Name:       s.Name + "." + m.Name.Token.Literal,
+			Parameters: m.Parameters,
+			Defaults:   m.Defaults,
+			Rest:       m.Rest,
+			Body:       m.Body,
+			Env:        env,
+			Generator:  m.Generator,
+			Async:      m.Async,
+		}
+	}
+
+	if err := bind(env, s.Name, s, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+// 调用结构体构造实例，实参按字段顺序绑定，也可以用字段名作为关键字参数
+func newInstance(s *object.Struct, args []object.Object, keywords []keywordArgument) object.Object {
+	values, err := constructorArguments(s.Name, s.Fields, args, keywords)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]object.Object, len(s.Fields))
+	for i, name := range s.Fields {
+		fields[name] = values[i]
+	}
+
+	return &object.Instance{Struct: s, Fields: fields}
+}
+
+// 把构造时的位置参数和关键字参数按字段顺序排列
+func constructorArguments(name string, fields []string, args []object.Object, keywords []keywordArgument) ([]object.Object, *object.Error) {
+	tooFew := len(keywords) == 0 && len(args) < len(fields)
+	if tooFew || len(args) > len(fields) {
+		return nil, newErrorOfKind(TYPE_ERROR, "wrong number of arguments to `%s`. got=%d, want=%d",
+			name, len(args), len(fields))
+	}
+
+	values := make([]object.Object, len(fields))
+	copy(values, args)
+
+	for _, kw := range keywords {
+		fieldIdx := -1
+		for i, field := range fields {
+			if field == kw.name {
+				fieldIdx = i
+				break
+			}
+		}
+		if fieldIdx < 0 {
+			return nil, newErrorOfKind(TYPE_ERROR, "unknown keyword argument `%s`", kw.name)
+		}
+		if values[fieldIdx] != nil {
+			return nil, newErrorOfKind(TYPE_ERROR, "multiple values for argument `%s`", kw.name)
+		}
+		values[fieldIdx] = kw.value
+	}
+
+	for i, field := range fields {
+		if values[i] == nil {
+			return nil, newErrorOfKind(TYPE_ERROR, "missing argument `%s`", field)
+		}
+	}
+
+	return values, nil
+}
+
+// 把方法的第一个参数绑定为self，返回接受其余参数的函数
+func bindMethod(method *object.Function, self object.Object) *object.Function {
+	bound := *method
+	bound.Parameters = method.Parameters[1:]
+	if method.Defaults != nil {
+		bound.Defaults = method.Defaults[1:]
+	}
+	bound.Env = object.NewEnclosedEnvironment(method.Env)
+	bound.Env.Set(method.Parameters[0].Token.Literal, self)
+
+	return &bound
+}
@@ -0,0 +1,66 @@
+package evaluator
+
+import "testing"
+
+const pointStruct = `
+struct Point {
+	x, y,
+	fn norm(self) { self.x * self.x + self.y * self.y },
+	fn add(self, other) { Point(self.x + other.x, self.y + other.y) },
+	fn scale(self, k = 2) { Point(x: self.x * k, y: self.y * k) }
+}
+`
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Point(1, 2)", "Point(x: 1, y: 2)"},
+		{"Point(y: 2, x: 1)", "Point(x: 1, y: 2)"},
+		{"Point(1, y: 2).y", "2"},
+		{"let p = Point(3, 4); p.x + p.y", "7"},
+		{"Point(3, 4).norm()", "25"},
+		{"Point(1, 2).add(Point(10, 20))", "Point(x: 11, y: 22)"},
+		{"Point(1, 2).scale()", "Point(x: 2, y: 4)"},
+		{"Point(1, 2).scale(k: 3).x", "3"},
+		{"let norm = Point(3, 4).norm; norm()", "25"},
+		{"Point.norm(Point(3, 4))", "25"},
+		{"Point(1, 2).norm", "<fn Point.norm/0>"},
+		{"Point", "<struct Point>"},
+		{"let p = Point(1, 2); p == p", "true"},
+		{"Point(1, 2) == Point(1, 2)", "false"},
+		{"if (true) { struct Local { a } Local(1).a }", "1"},
+		{"struct Wrapper { inner } Wrapper(Point(1, 2)).inner.y", "2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(pointStruct + tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. want=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Point(1)", "wrong number of arguments to `Point`. got=1, want=2"},
+		{"Point(1, 2, 3)", "wrong number of arguments to `Point`. got=3, want=2"},
+		{"Point(x: 1)", "missing argument `y`"},
+		{"Point(1, x: 2)", "multiple values for argument `x`"},
+		{"Point(1, z: 2)", "unknown keyword argument `z`"},
+		{"Point(1, 2).z", "`Point` has no field or method `z`"},
+		{"Point.x", "`Point` has no method `x`"},
+		{"5.x", "INTEGER has no method `x`"},
+		{"Point(1, 2).add(1)", "INTEGER has no method `x`"},
+		{"const Point = 1; struct Point { x }", "cannot reassign constant Point"},
+		{"if (true) { struct Local { a } }; Local", "identifier not found: Local"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(pointStruct+tt.input), tt.expected)
+	}
+}
@@ -0,0 +1,49 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 求值switch表达式
+// 依次比较每个case的值，第一个与subject相等的case执行其语句，不会贯穿到下一个case；
+// 没有case匹配时执行default，没有default则返回null
+func evalSwitchExpression(se *ast.SwitchExpression, env *object.Environment) object.Object {
+	subject := Eval(se.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, c := range se.Cases {
+		for _, v := range c.Values {
+			value := Eval(v, env)
+			if isError(value) {
+				return value
+			}
+
+			equal := switchValuesEqual(subject, value)
+			if isError(equal) {
+				return equal
+			}
+			if equal == TRUE {
+				return evalCaseBody(c.Body, blockEnvironment(c.Body, env))
+			}
+		}
+	}
+
+	if se.Default != nil {
+		return evalCaseBody(se.Default, blockEnvironment(se.Default, env))
+	}
+	return NULL
+}
+
+// 比较switch的subject与case值，subject是重载了__eq__的结构体实例时调用该方法
+func switchValuesEqual(subject, value object.Object) object.Object {
+	if result, ok := evalOperatorMethod("==", subject, value); ok {
+		if isError(result) {
+			return result
+		}
+		return nativeBoolToBooleanObject(isTruthy(result))
+	}
+	return nativeBoolToBooleanObject(objectsEqual(subject, value))
+}
@@ -0,0 +1,67 @@
+package evaluator
+
+import "testing"
+
+func TestElseIfExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"if (false) { 1 } else if (true) { 2 } else { 3 }", 2},
+		{"if (false) { 1 } else if (false) { 2 } else { 3 }", 3},
+		{"if (true) { 1 } else if (true) { 2 } else { 3 }", 1},
+		{"if (false) { 1 } else if (false) { 2 }", nil},
+		{"let x = 15; if (x < 10) { 1 } else if (x < 20) { 2 } else if (x < 30) { 3 }", 2},
+		{"let f = fn(x) { if (x < 0) { return -1 } else if (x == 0) { return 0 }; 1 }; f(-5) * 100 + f(0) * 10 + f(5)", -99},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestSwitchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`switch (1) { case 1: "one" case 2: "two" }`, "one"},
+		{`switch (2) { case 1: "one" case 2: "two" }`, "two"},
+		{`switch (3) { case 1, 2: "small" case 3, 4: "medium" default: "large" }`, "medium"},
+		{`switch (9) { case 1, 2: "small" default: "large" }`, "large"},
+		{`switch (9) { case 1: "one" }`, nil},
+		{`switch ("b") { case "a": 1 case "b": 2 }`, 2},
+		{`switch ([1, 2]) { case [1, 2]: "pair" default: "other" }`, "pair"},
+		{`let x = 4; switch (x / 2) { case 2: "two" default: "other" }`, "two"},
+		{`switch (true) { case 1 < 2: "lt" default: "ge" }`, "lt"},
+		{`switch (1) { case 1: let y = 5; y * 2 }`, 10},
+		{`let y = 1; switch (1) { case 1: let y = 5; y }; y`, 1},
+		{`switch (1) { case 1: }`, nil},
+		{`let f = fn(x) { switch (x) { case 1: return "early" }; "late" }; f(1) + f(2)`, "earlylate"},
+		{`struct P { x, fn __eq__(self, o) { self.x == o } }; switch (P(3)) { case 1: "one" case 3: "three" }`, "three"},
+		{`switch (1 + true) { case 1: 1 }`, "type mismatch: INTEGER + BOOLEAN"},
+		{`switch (1) { case 1 + true: 1 }`, "type mismatch: INTEGER + BOOLEAN"},
+		{`switch (2) { case 1: 1 default: -true }`, "unknown operator: -BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected && evaluated.Inspect() != "ERROR: "+expected {
+				t.Errorf("wrong result for %s. want=%q, got=%q",
+					tt.input, expected, evaluated.Inspect())
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"sync"
+	"time"
+)
+
+// 时间在脚本中以INTEGER表示：时刻为Unix毫秒数，时长为毫秒数
+// 因此时长运算直接使用整数加减即可，如now() + duration("1h")
+
+// 时钟接口，嵌入方或测试可通过SetClock替换
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// 手动时钟，时间只在Sleep或Advance时前进，用于冻结时间
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Sleep(d time.Duration) { c.Advance(d) }
+
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// 当前时钟，SetClock可能与spawn启动的任务并发，读写都需要加锁
+var (
+	clockMu sync.RWMutex
+	clock   Clock = systemClock{}
+)
+
+// 替换求值器使用的时钟，传入nil恢复系统时钟
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	clock = c
+}
+
+func currentClock() Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return clock
+}
+
+func timeToObject(t time.Time) *object.Integer {
+	return &object.Integer{Value: t.UnixMilli()}
+}
+
+// 取得可选的格式参数，默认RFC3339
+func timeLayout(name string, args []object.Object, idx int) (string, *object.Error) {
+	if len(args) <= idx {
+		return time.RFC3339, nil
+	}
+	layout, ok := args[idx].(*object.String)
+	if !ok {
+		return "", newError("layout to `%s` must be STRING, got %s",
+			name, args[idx].Type())
+	}
+	return layout.Value, nil
+}
+
+// now()
+// 当前时刻（Unix毫秒）
+func timeNow(args ...object.Object) object.Object {
+	if len(args) != 0 {
+		return newError("wrong number of arguments. got=%d, want=0",
+			len(args))
+	}
+	return timeToObject(currentClock().Now())
+}
+
+// format_time(ms[, layout])
+// 按Go的时间格式输出UTC时间
+func timeFormat(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	ms, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `format_time` must be INTEGER, got %s",
+			args[0].Type())
+	}
+	layout, errObj := timeLayout("format_time", args, 1)
+	if errObj != nil {
+		return errObj
+	}
+
+	return &object.String{Value: time.UnixMilli(ms.Value).UTC().Format(layout)}
+}
+
+// parse_time(str[, layout])
+// 解析时间字符串，返回Unix毫秒
+func timeParse(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments. got=%d, want=1 or 2",
+			len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `parse_time` must be STRING, got %s",
+			args[0].Type())
+	}
+	layout, errObj := timeLayout("parse_time", args, 1)
+	if errObj != nil {
+		return errObj
+	}
+
+	t, err := time.Parse(layout, str.Value)
+	if err != nil {
+		return newError("invalid time: %s", err)
+	}
+
+	return timeToObject(t)
+}
+
+// duration(str)
+// 解析"1h30m"形式的时长，返回毫秒数
+func timeDuration(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	str, ok := args[0].(*object.String)
+	if !ok {
+		return newError("argument to `duration` must be STRING, got %s",
+			args[0].Type())
+	}
+
+	d, err := time.ParseDuration(str.Value)
+	if err != nil {
+		return newError("invalid duration: %s", err)
+	}
+
+	return &object.Integer{Value: d.Milliseconds()}
+}
+
+// format_duration(ms)
+// 毫秒数格式化为"1h30m0s"形式
+func timeFormatDuration(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	ms, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `format_duration` must be INTEGER, got %s",
+			args[0].Type())
+	}
+
+	return &object.String{Value: (time.Duration(ms.Value) * time.Millisecond).String()}
+}
+
+// sleep(ms)
+func timeSleep(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments. got=%d, want=1",
+			len(args))
+	}
+	ms, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument to `sleep` must be INTEGER, got %s",
+			args[0].Type())
+	}
+	if ms.Value < 0 {
+		return newError("argument to `sleep` must not be negative, got %d", ms.Value)
+	}
+
+	currentClock().Sleep(time.Duration(ms.Value) * time.Millisecond)
+	return NULL
+}
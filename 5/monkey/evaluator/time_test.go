@@ -0,0 +1,79 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeBuiltins(t *testing.T) {
+	frozen := NewManualClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	SetClock(frozen)
+	defer SetClock(nil)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`now()`, 1709294400000},
+		{`format_time(now())`, "2024-03-01T12:00:00Z"},
+		{`format_time(now() + duration("1h30m"), "2006-01-02 15:04")`, "2024-03-01 13:30"},
+		{`parse_time("2024-03-01T12:00:00Z") == now()`, true},
+		{`parse_time("01/03/2024", "02/01/2006")`, 1709251200000},
+		{`duration("1m") - duration("30s")`, 30000},
+		{`format_duration(duration("90m"))`, "1h30m0s"},
+		{`let start = now(); sleep(250); now() - start`, 250},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("wrong result for %s. want=%q, got=%q",
+					tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestTimeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`now(1)`, "wrong number of arguments. got=1, want=0"},
+		{`format_time("x")`, "argument to `format_time` must be INTEGER, got STRING"},
+		{`format_time(0, 1)`, "layout to `format_time` must be STRING, got INTEGER"},
+		{`parse_time("yesterday")`, `invalid time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`},
+		{`duration("soon")`, `invalid duration: time: invalid duration "soon"`},
+		{`sleep(-1)`, "argument to `sleep` must not be negative, got -1"},
+	}
+
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestSetClockWhileTasksRun(t *testing.T) {
+	defer SetClock(nil)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetClock(NewManualClock(start))
+		}
+	}()
+
+	evaluated := testEval("let tasks = for (i in range(10)) { spawn(fn() { now() > -1 }) }; for (t in tasks) { join(t) }")
+	<-done
+
+	if evaluated.Inspect() != "[true, true, true, true, true, true, true, true, true, true]" {
+		t.Errorf("wrong result. got=%q", evaluated.Inspect())
+	}
+}
@@ -0,0 +1,96 @@
+package evaluator
+
+import (
+	"monkey/ast"
+	"monkey/object"
+)
+
+// 求值try表达式
+// try块产生错误时执行catch块，错误以哈希形式绑定到catch参数（生成器被结束时除外）；
+// finally块总会执行，若其中产生错误或return则覆盖之前的结果
+func evalTryExpression(te *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(te.Block, env)
+
+	if err, ok := result.(*object.Error); ok && te.Catch != nil && err.Kind != GENERATOR_CLOSED {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if te.CatchParam != nil {
+			catchEnv.Set(te.CatchParam.Token.Literal, errorToHash(err))
+		}
+		result = evalBlockStatement(te.Catch, catchEnv)
+	}
+
+	if te.Finally != nil {
+		finally := Eval(te.Finally, env)
+		if finally != nil {
+			rt := finally.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return finally
+			}
+		}
+	}
+
+	if result == nil {
+		return NULL
+	}
+
+	return result
+}
+
+// 错误对象转换为catch中可检查的哈希
+// {"message": ..., "kind": ..., "line": ..., "column": ..., "stack": [...]}
+func errorToHash(err *object.Error) *object.Hash {
+	pairs := make(map[object.HashKey]object.HashPair)
+
+	set := func(key string, value object.Object) {
+		k := &object.String{Value: key}
+		pairs[k.HashKey()] = object.HashPair{Key: k, Value: value}
+	}
+
+	set("message", &object.String{Value: err.Message})
+	set("kind", &object.String{Value: err.Kind})
+	set("line", &object.Integer{Value: int64(err.Line)})
+	set("column", &object.Integer{Value: int64(err.Column)})
+
+	stack := make([]object.Object, len(err.Stack))
+	for i, frame := range err.Stack {
+		stack[i] = &object.String{Value: frame}
+	}
+	set("stack", &object.Array{Elements: stack})
+
+	return &object.Hash{Pairs: pairs}
+}
+
+// throw的值转换为错误对象
+// 字符串作为错误信息；哈希可以提供message和kind（catch得到的哈希可以原样重新抛出）；
+// 其他值使用其Inspect结果作为错误信息
+func newThrownError(val object.Object) *object.Error {
+	err := &object.Error{Message: val.Inspect(), Kind: THROWN_ERROR}
+
+	switch val := val.(type) {
+	case *object.String:
+		err.Message = val.Value
+	case *object.Hash:
+		get := func(key string) (object.Object, bool) {
+			pair, ok := val.Pairs[(&object.String{Value: key}).HashKey()]
+			return pair.Value, ok
+		}
+		if message, ok := get("message"); ok {
+			err.Message = message.Inspect()
+		}
+		if kind, ok := get("kind"); ok {
+			err.Kind = kind.Inspect()
+		}
+		if line, ok := get("line"); ok {
+			if line, ok := line.(*object.Integer); ok {
+				err.Line = int(line.Value)
+			}
+		}
+		if column, ok := get("column"); ok {
+			if column, ok := column.(*object.Integer); ok {
+				err.Column = int(column.Value)
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,86 @@
+package evaluator
+
+import (
+	"monkey/object"
+	"testing"
+)
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`try { 1 } catch (e) { 2 }`, 1},
+		{`try { throw "boom"; 1 } catch (e) { 2 }`, 2},
+		{`try { throw "boom" } catch (e) { e["message"] }`, "boom"},
+		{`try { throw "boom" } catch (e) { e["kind"] }`, "Error"},
+		{`try { 1 + true } catch (e) { e["kind"] }`, "TypeError"},
+		{`try { 1 + true } catch (e) { e["message"] }`, "type mismatch: INTEGER + BOOLEAN"},
+		{`try { foobar } catch (e) { e["kind"] }`, "NameError"},
+		{`try { len(1) } catch (e) { e["kind"] }`, "RuntimeError"},
+		{`try { throw {"message": "bad input", "kind": "ValueError"} } catch (e) { e["kind"] + ": " + e["message"] }`,
+			"ValueError: bad input"},
+		{`try { throw 42 } catch (e) { e["message"] }`, "42"},
+		{`try { throw "x" } catch { 3 }`, 3},
+		{`let f = fn() { throw "inner" }; try { f(); 1 } catch (e) { e["message"] }`, "inner"},
+		{`let f = fn() { try { return 1; } finally { 2 } }; f()`, 1},
+		{`let f = fn() { try { throw "x" } finally { return 5; } }; f()`, 5},
+		{`try { try { throw "a" } catch (e) { throw e } } catch (e) { e["message"] }`, "a"},
+		{`try { 1 } finally { 2 }`, 1},
+		{`try { throw "boom" } catch (e) { }`, nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String for %s. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("wrong value for %s. want=%q, got=%q", tt.input, expected, str.Value)
+			}
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestUncaughtErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+		kind    string
+		line    int
+		column  int
+	}{
+		{`throw "boom"`, "boom", "Error", 1, 1},
+		{"let x = 1;\n  x + true", "type mismatch: INTEGER + BOOLEAN", "TypeError", 2, 5},
+		{"let f = fn() {\n  missing\n};\nf()", "identifier not found: missing", "NameError", 2, 3},
+		{`len(1)`, "argument to `len` not supported, got INTEGER", "RuntimeError", 1, 4},
+		{`try { throw "a" } finally { 1 }`, "a", "Error", 1, 7},
+		{`try { throw "a" } catch (e) { throw "b" }`, "b", "Error", 1, 31},
+		{"try {\n  -true\n} catch (e) {\n  throw e\n}", "unknown operator: -BOOLEAN", "TypeError", 2, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("object is not Error for %q. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.message || errObj.Kind != tt.kind {
+			t.Errorf("wrong error for %q. want=%s(%q), got=%s(%q)",
+				tt.input, tt.kind, tt.message, errObj.Kind, errObj.Message)
+		}
+		if errObj.Line != tt.line || errObj.Column != tt.column {
+			t.Errorf("wrong position for %q. want=%d:%d, got=%d:%d",
+				tt.input, tt.line, tt.column, errObj.Line, errObj.Column)
+		}
+	}
+}
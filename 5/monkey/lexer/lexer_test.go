@@ -30,6 +30,15 @@ if (5 < 10) {
 [1, 2];
 {"foo": "bar"}
 macro(x,y){x+y;};
+match x { [a, ...b] => a }
+for (k, v in h) {}
+yield x;
+struct P { x }
+enum E { A }
+xs |> map(|x| x)
+null ?? a?.b?[c]
+p.x
+switch (x) { case 1: default: }
 `
 
 	tests := []struct {
@@ -135,6 +144,72 @@ macro(x,y){x+y;};
 		{token.SEMICOLON, ";"},
 		{token.RBRACE, "}"},
 		{token.SEMICOLON, ";"},
+		{token.MATCH, "match"},
+		{token.IDENT, "x"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "b"},
+		{token.RBRACKET, "]"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.RBRACE, "}"},
+		{token.FOR, "for"},
+		{token.LPAREN, "("},
+		{token.IDENT, "k"},
+		{token.COMMA, ","},
+		{token.IDENT, "v"},
+		{token.IN, "in"},
+		{token.IDENT, "h"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.YIELD, "yield"},
+		{token.IDENT, "x"},
+		{token.SEMICOLON, ";"},
+		{token.STRUCT, "struct"},
+		{token.IDENT, "P"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.ENUM, "enum"},
+		{token.IDENT, "E"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "A"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "xs"},
+		{token.PIPELINE, "|>"},
+		{token.IDENT, "map"},
+		{token.LPAREN, "("},
+		{token.BAR, "|"},
+		{token.IDENT, "x"},
+		{token.BAR, "|"},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.NULL, "null"},
+		{token.NULLISH, "??"},
+		{token.IDENT, "a"},
+		{token.OPTIONAL_DOT, "?."},
+		{token.IDENT, "b"},
+		{token.OPTIONAL_BRACKET, "?["},
+		{token.IDENT, "c"},
+		{token.RBRACKET, "]"},
+		{token.IDENT, "p"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.SWITCH, "switch"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.CASE, "case"},
+		{token.INT, "1"},
+		{token.COLON, ":"},
+		{token.DEFAULT, "default"},
+		{token.COLON, ":"},
+		{token.RBRACE, "}"},
 		{token.EOF, ""},
 	}
 
@@ -154,3 +229,56 @@ macro(x,y){x+y;};
 		}
 	}
 }
+
+func TestTokenPosition(t *testing.T) {
+	input := `let x = 5;
+try {
+  throw "oops";
+} catch (e) { e } finally { x }`
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedLine   int
+		expectedColumn int
+	}{
+		{token.LET, 1, 1},
+		{token.IDENT, 1, 5},
+		{token.ASSIGN, 1, 7},
+		{token.INT, 1, 9},
+		{token.SEMICOLON, 1, 10},
+		{token.TRY, 2, 1},
+		{token.LBRACE, 2, 5},
+		{token.THROW, 3, 3},
+		{token.STRING, 3, 9},
+		{token.SEMICOLON, 3, 15},
+		{token.RBRACE, 4, 1},
+		{token.CATCH, 4, 3},
+		{token.LPAREN, 4, 9},
+		{token.IDENT, 4, 10},
+		{token.RPAREN, 4, 11},
+		{token.LBRACE, 4, 13},
+		{token.IDENT, 4, 15},
+		{token.RBRACE, 4, 17},
+		{token.FINALLY, 4, 19},
+		{token.LBRACE, 4, 27},
+		{token.IDENT, 4, 29},
+		{token.RBRACE, 4, 31},
+		{token.EOF, 4, 32},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 通道，在spawn启动的任务之间传递对象
+type Channel struct {
+	Value chan Object
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func NewChannel(size int) *Channel {
+	return &Channel{Value: make(chan Object, size)}
+}
+
+func (c *Channel) Type() ObjectType { return CHANNEL_OBJ }
+func (c *Channel) Inspect() string {
+	return fmt.Sprintf("<channel %d/%d>", len(c.Value), cap(c.Value))
+}
+
+// 关闭通道，已经关闭时返回false
+func (c *Channel) Close() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	c.closed = true
+	close(c.Value)
+	return true
+}
+
+// 迭代通道中收到的值，直到通道关闭，键为接收的序号
+func (c *Channel) Iter() Iterator {
+	return &channelIterator{ch: c.Value}
+}
+
+type channelIterator struct {
+	ch    chan Object
+	index int64
+}
+
+func (it *channelIterator) Next() (Object, Object, bool) {
+	value, ok := <-it.ch
+	if !ok {
+		return nil, nil, false
+	}
+	key := &Integer{Value: it.index}
+	it.index++
+	return key, value, true
+}
+
+// spawn启动的任务
+type Task struct {
+	Done   chan struct{} // 任务结束时关闭
+	Result Object        // 任务的返回值，Done关闭后可读
+}
+
+func (t *Task) Type() ObjectType { return TASK_OBJ }
+func (t *Task) Inspect() string {
+	select {
+	case <-t.Done:
+		return "<task done>"
+	default:
+		return "<task running>"
+	}
+}
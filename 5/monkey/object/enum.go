@@ -0,0 +1,112 @@
+package object
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+)
+
+// 枚举类型，由若干变体组成
+type Enum struct {
+	Name     string
+	Variants []*Variant // 按声明顺序
+
+	id uint64 // 同名的两次声明是不同的枚举，哈希键按编号区分
+}
+
+var enumIDs atomic.Uint64
+
+func NewEnum(name string) *Enum {
+	return &Enum{Name: name, id: enumIDs.Add(1)}
+}
+
+func (e *Enum) Type() ObjectType { return ENUM_OBJ }
+func (e *Enum) Inspect() string  { return "<enum " + e.Name + ">" }
+
+// 按名称查找变体，不存在时返回nil
+func (e *Enum) Variant(name string) *Variant {
+	for _, v := range e.Variants {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// 枚举变体，有字段的变体调用时构造枚举值，无字段的变体只有唯一的值Unit
+type Variant struct {
+	Enum   *Enum
+	Name   string
+	Fields []string
+	Unit   *EnumValue // 无字段的变体的值，有字段时为nil
+}
+
+func (v *Variant) Type() ObjectType { return VARIANT_OBJ }
+func (v *Variant) Inspect() string  { return "<variant " + v.FullName() + ">" }
+
+// 带枚举名的变体名，如Shape.Circle
+func (v *Variant) FullName() string { return v.Enum.Name + "." + v.Name }
+
+// 枚举值，按值比较，各字段都能作为哈希键时可以作为哈希键
+type EnumValue struct {
+	Variant *Variant
+	Values  []Object // 与Variant.Fields一一对应
+}
+
+func (ev *EnumValue) Type() ObjectType { return ENUM_VALUE_OBJ }
+func (ev *EnumValue) Inspect() string {
+	if ev.Variant.Unit != nil {
+		return ev.Variant.FullName()
+	}
+
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i, name := range ev.Variant.Fields {
+		fields = append(fields, name+": "+ev.Values[i].Inspect())
+	}
+
+	out.WriteString(ev.Variant.FullName())
+	out.WriteString("(")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// 按名称读取字段
+func (ev *EnumValue) Field(name string) (Object, bool) {
+	for i, field := range ev.Variant.Fields {
+		if field == name {
+			return ev.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+func (ev *EnumValue) hashable() bool {
+	for _, value := range ev.Values {
+		if _, ok := HashKeyOf(value); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// 由变体（所属枚举的编号和变体名）和各字段的哈希键计算，与按变体比较的相等性一致
+// 调用前应通过HashKeyOf确认可以作为哈希键
+func (ev *EnumValue) HashKey() HashKey {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, ev.Variant.Enum.id)
+	h.Write([]byte(ev.Variant.Name))
+
+	for _, value := range ev.Values {
+		key, _ := HashKeyOf(value)
+		h.Write([]byte(key.Type))
+		binary.Write(h, binary.LittleEndian, key.Value)
+	}
+
+	return HashKey{Type: ev.Type(), Value: h.Sum64()}
+}
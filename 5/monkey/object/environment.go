@@ -1,12 +1,20 @@
 package object
 
+import "sync"
+
+// 环境可以被spawn启动的多个goroutine共享（闭包捕获同一个环境），读写都需要加锁
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	mu     sync.RWMutex
+	store  map[string]Object
+	consts map[string]bool // const绑定的名称，只在有const绑定时创建
+	outer  *Environment
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
+	e.mu.RLock()
 	obj, ok := e.store[name]
+	e.mu.RUnlock()
+
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
 	}
@@ -14,10 +22,40 @@ func (e *Environment) Get(name string) (Object, bool) {
 }
 
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
 	e.store[name] = val
+	e.mu.Unlock()
+
 	return val
 }
 
+// 在本环境中绑定名称，constant为true时绑定为常量
+// 常量不能在同一环境中被重新绑定（内层环境仍可遮蔽），此时不绑定并返回false
+func (e *Environment) Define(name string, val Object, constant bool) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.consts[name] {
+		return false
+	}
+	if constant {
+		if e.consts == nil {
+			e.consts = make(map[string]bool)
+		}
+		e.consts[name] = true
+	}
+	e.store[name] = val
+
+	return true
+}
+
+// 名称是否是本环境（不含外层环境）中的常量
+func (e *Environment) IsConst(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.consts[name]
+}
+
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
 	return &Environment{store: s, outer: nil}
@@ -0,0 +1,195 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// 迭代器
+// Next返回下一个元素的键（下标、哈希键等）和值，没有更多元素时ok为false
+type Iterator interface {
+	Next() (key, value Object, ok bool)
+}
+
+// 可以提前关闭的迭代器，迭代没有进行到最后就结束时调用Close释放资源
+type Closer interface {
+	Close()
+}
+
+// 可迭代对象，用于for-in
+// 宿主程序中的类型实现该接口后同样可以在Monkey中迭代
+type Iterable interface {
+	Object
+	Iter() Iterator
+}
+
+// 迭代数组元素，键为下标
+type arrayIterator struct {
+	elements []Object
+	index    int
+}
+
+func (ao *Array) Iter() Iterator {
+	return &arrayIterator{elements: ao.Elements}
+}
+
+func (it *arrayIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.elements) {
+		return nil, nil, false
+	}
+	key := &Integer{Value: int64(it.index)}
+	value := it.elements[it.index]
+	it.index++
+	return key, value, true
+}
+
+// 迭代哈希的键值对
+// 哈希本身无序，按键排序以保证每次迭代顺序相同：
+// 先按类型（BOOLEAN、INTEGER、STRING），同类型再按值
+type hashIterator struct {
+	pairs []HashPair
+	index int
+}
+
+func (h *Hash) Iter() Iterator {
+	return &hashIterator{pairs: h.SortedPairs()}
+}
+
+func (it *hashIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.pairs) {
+		return nil, nil, false
+	}
+	pair := it.pairs[it.index]
+	it.index++
+	return pair.Key, pair.Value, true
+}
+
+// 按键排序的键值对
+func (h *Hash) SortedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(h.Pairs))
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return lessKey(pairs[i].Key, pairs[j].Key)
+	})
+
+	return pairs
+}
+
+func lessKey(a, b Object) bool {
+	if a.Type() != b.Type() {
+		return a.Type() < b.Type()
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		return a.Value < b.(*Integer).Value
+	case *Boolean:
+		return !a.Value && b.(*Boolean).Value
+	default:
+		return a.Inspect() < b.Inspect()
+	}
+}
+
+// 迭代字符串中的字符，键为字符下标
+type stringIterator struct {
+	chars []rune
+	index int
+}
+
+func (s *String) Iter() Iterator {
+	return &stringIterator{chars: []rune(s.Value)}
+}
+
+func (it *stringIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.chars) {
+		return nil, nil, false
+	}
+	key := &Integer{Value: int64(it.index)}
+	value := &String{Value: string(it.chars[it.index])}
+	it.index++
+	return key, value, true
+}
+
+// 整数区间[Start, End)，按Step递增（Step为负数时递减）
+// 迭代时才逐个产生元素，不占用额外内存
+type Range struct {
+	Start int64
+	End   int64
+	Step  int64
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	return fmt.Sprintf("range(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+
+func (r *Range) Iter() Iterator {
+	return &rangeIterator{r: r, next: r.Start}
+}
+
+type rangeIterator struct {
+	r     *Range
+	next  int64
+	index int64
+	done  bool
+}
+
+func (it *rangeIterator) Next() (Object, Object, bool) {
+	if it.done || (it.r.Step > 0 && it.next >= it.r.End) || (it.r.Step < 0 && it.next <= it.r.End) {
+		return nil, nil, false
+	}
+
+	key := &Integer{Value: it.index}
+	value := &Integer{Value: it.next}
+
+	// 溢出时结束迭代
+	next := it.next + it.r.Step
+	if (it.r.Step > 0) != (next > it.next) {
+		it.done = true
+	}
+	it.next = next
+	it.index++
+
+	return key, value, true
+}
+
+// 生成器，调用含yield的函数时返回
+// 只能迭代一次，迭代器就是生成器本身
+type Generator struct {
+	Name   string
+	Resume func() (value Object, done bool) // 继续执行到下一个yield，函数执行完毕时done为true
+	Stop   func()                           // 提前结束生成器，释放其占用的资源
+
+	mu    sync.Mutex
+	index int64
+}
+
+func (g *Generator) Type() ObjectType { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string {
+	if g.Name == "" {
+		return "<generator>"
+	}
+	return "<generator " + g.Name + ">"
+}
+
+func (g *Generator) Iter() Iterator { return g }
+
+func (g *Generator) Close() { g.Stop() }
+
+func (g *Generator) Next() (Object, Object, bool) {
+	value, done := g.Resume()
+	if done {
+		return nil, nil, false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := &Integer{Value: g.index}
+	g.index++
+
+	return key, value, true
+}
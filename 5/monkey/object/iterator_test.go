@@ -0,0 +1,88 @@
+package object
+
+import "testing"
+
+func collect(it Iterator) (keys, values []string) {
+	for {
+		key, value, ok := it.Next()
+		if !ok {
+			return keys, values
+		}
+		keys = append(keys, key.Inspect())
+		values = append(values, value.Inspect())
+	}
+}
+
+func TestIterators(t *testing.T) {
+	hash := &Hash{Pairs: map[HashKey]HashPair{}}
+	for _, key := range []Object{
+		&String{Value: "b"}, &Integer{Value: 10}, &String{Value: "a"},
+		&Integer{Value: 2}, &Boolean{Value: true},
+	} {
+		hash.Pairs[key.(Hashable).HashKey()] = HashPair{Key: key, Value: &String{Value: "v" + key.Inspect()}}
+	}
+
+	tests := []struct {
+		iterable       Iterable
+		expectedKeys   []string
+		expectedValues []string
+	}{
+		{
+			&Array{Elements: []Object{&Integer{Value: 5}, &String{Value: "x"}}},
+			[]string{"0", "1"},
+			[]string{"5", "x"},
+		},
+		{
+			hash,
+			[]string{"true", "2", "10", "a", "b"},
+			[]string{"vtrue", "v2", "v10", "va", "vb"},
+		},
+		{
+			&String{Value: "héllo"},
+			[]string{"0", "1", "2", "3", "4"},
+			[]string{"h", "é", "l", "l", "o"},
+		},
+		{
+			&Range{Start: 0, End: 5, Step: 2},
+			[]string{"0", "1", "2"},
+			[]string{"0", "2", "4"},
+		},
+		{
+			&Range{Start: 3, End: 0, Step: -1},
+			[]string{"0", "1", "2"},
+			[]string{"3", "2", "1"},
+		},
+		{
+			&Range{Start: 9223372036854775806, End: 9223372036854775807, Step: 5},
+			[]string{"0"},
+			[]string{"9223372036854775806"},
+		},
+		{
+			&Range{Start: 1, End: 1, Step: 1},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		keys, values := collect(tt.iterable.Iter())
+		if !equalStrings(keys, tt.expectedKeys) {
+			t.Errorf("wrong keys for %s. want=%v, got=%v", tt.iterable.Inspect(), tt.expectedKeys, keys)
+		}
+		if !equalStrings(values, tt.expectedValues) {
+			t.Errorf("wrong values for %s. want=%v, got=%v", tt.iterable.Inspect(), tt.expectedValues, values)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"monkey/ast"
+	"regexp"
 	"strings"
 )
 
@@ -26,6 +27,21 @@ const (
 
 	QUOTE_OBJ = "QUOTE"
 	MACRO_OBJ = "MACRO"
+
+	REGEXP_OBJ = "REGEXP"
+	RANGE_OBJ  = "RANGE"
+
+	GENERATOR_OBJ = "GENERATOR"
+	CHANNEL_OBJ   = "CHANNEL"
+	TASK_OBJ      = "TASK"
+	PROMISE_OBJ   = "PROMISE"
+
+	STRUCT_OBJ   = "STRUCT"
+	INSTANCE_OBJ = "INSTANCE"
+
+	ENUM_OBJ       = "ENUM"
+	VARIANT_OBJ    = "VARIANT"
+	ENUM_VALUE_OBJ = "ENUM_VALUE"
 )
 
 type ObjectType string
@@ -39,6 +55,19 @@ type Hashable interface {
 	HashKey() HashKey
 }
 
+// 取得值的哈希键，值不能作为哈希键时返回false
+// 枚举值只有在各字段都能作为哈希键时才能作为哈希键
+func HashKeyOf(obj Object) (HashKey, bool) {
+	if v, ok := obj.(*EnumValue); ok && !v.hashable() {
+		return HashKey{}, false
+	}
+	h, ok := obj.(Hashable)
+	if !ok {
+		return HashKey{}, false
+	}
+	return h.HashKey(), true
+}
+
 type Object interface {
 	Type() ObjectType
 	Inspect() string
@@ -86,34 +115,54 @@ func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
 type Error struct {
 	Message string
+	Kind    string // 错误类别，如TypeError、NameError
+	Line    int    // 出错位置，0表示未知
+	Column  int
+	Stack   []string // 错误经过的函数调用，由内向外
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
 type Function struct {
+	Name       string // 函数名，匿名函数为空
 	Parameters []*ast.Identifier
+	Defaults   []ast.Expression // 参数默认值，调用时在函数环境中求值
+	Rest       *ast.Identifier  // 剩余参数，可为nil
 	Body       *ast.BlockStatement
 	Env        *Environment
+	Generator  bool // 调用时返回生成器而不是执行函数体
+	Async      bool // 调用时返回promise，函数体可以await
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
 func (f *Function) Inspect() string {
-	var out bytes.Buffer
-
-	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
+	if f.Name == "" {
+		return "<fn/" + f.Arity() + ">"
 	}
+	return "<fn " + f.Name + "/" + f.Arity() + ">"
+}
 
-	out.WriteString("fn")
-	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") ")
-	out.WriteString(f.Body.String())
-	out.WriteString("\n")
+// 必须传入的参数个数
+func (f *Function) RequiredParameters() int {
+	required := len(f.Parameters)
+	for required > 0 && f.Defaults != nil && f.Defaults[required-1] != nil {
+		required--
+	}
+	return required
+}
 
-	return out.String()
+// 参数个数描述，如2、1..3、1+
+func (f *Function) Arity() string {
+	required := f.RequiredParameters()
+	switch {
+	case f.Rest != nil:
+		return fmt.Sprintf("%d+", required)
+	case required == len(f.Parameters):
+		return fmt.Sprintf("%d", required)
+	default:
+		return fmt.Sprintf("%d..%d", required, len(f.Parameters))
+	}
 }
 
 type String struct {
@@ -132,7 +181,8 @@ func (s *String) HashKey() HashKey {
 type BuiltinFunction func(args ...Object) Object
 
 type Builtin struct {
-	Fn BuiltinFunction
+	Fn       BuiltinFunction
+	Keywords []string // 接受的关键字参数名，非空时选项哈希作为最后一个实参传入
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
@@ -217,3 +267,10 @@ func (m *Macro) Inspect() string {
 
 	return out.String()
 }
+
+type Regexp struct {
+	Value *regexp.Regexp
+}
+
+func (r *Regexp) Type() ObjectType { return REGEXP_OBJ }
+func (r *Regexp) Inspect() string  { return "regex(" + r.Value.String() + ")" }
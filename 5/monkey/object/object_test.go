@@ -58,3 +58,31 @@ func TestIntegerHashKey(t *testing.T) {
 		t.Errorf("integers with twoerent content have same hash keys")
 	}
 }
+
+func TestEnumValueHashKey(t *testing.T) {
+	shape := &Enum{Name: "Shape"}
+	circle := &Variant{Enum: shape, Name: "Circle", Fields: []string{"r"}}
+	square := &Variant{Enum: shape, Name: "Square", Fields: []string{"s"}}
+
+	one1 := &EnumValue{Variant: circle, Values: []Object{&Integer{Value: 1}}}
+	one2 := &EnumValue{Variant: circle, Values: []Object{&Integer{Value: 1}}}
+	two := &EnumValue{Variant: circle, Values: []Object{&Integer{Value: 2}}}
+	square1 := &EnumValue{Variant: square, Values: []Object{&Integer{Value: 1}}}
+	array := &EnumValue{Variant: circle, Values: []Object{&Array{}}}
+
+	if one1.HashKey() != one2.HashKey() {
+		t.Errorf("enum values with same content have different hash keys")
+	}
+
+	if one1.HashKey() == two.HashKey() {
+		t.Errorf("enum values with different content have same hash keys")
+	}
+
+	if one1.HashKey() == square1.HashKey() {
+		t.Errorf("enum values of different variants have same hash keys")
+	}
+
+	if _, ok := HashKeyOf(array); ok {
+		t.Errorf("enum value holding an array is usable as hash key")
+	}
+}
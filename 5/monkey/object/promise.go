@@ -0,0 +1,88 @@
+package object
+
+import "sync"
+
+type PromiseState string
+
+const (
+	PROMISE_PENDING   PromiseState = "pending"
+	PROMISE_FULFILLED PromiseState = "fulfilled"
+	PROMISE_REJECTED  PromiseState = "rejected"
+)
+
+// 异步操作的结果，只能从pending变为fulfilled或rejected一次
+type Promise struct {
+	mu        sync.Mutex
+	state     PromiseState
+	value     Object // fulfilled时为结果，rejected时为原因
+	callbacks []func()
+	abandoned []func() // promise再也不会被确定时执行
+}
+
+func NewPromise() *Promise {
+	return &Promise{state: PROMISE_PENDING}
+}
+
+func (p *Promise) Type() ObjectType { return PROMISE_OBJ }
+func (p *Promise) Inspect() string {
+	state, value := p.State()
+	if state == PROMISE_PENDING {
+		return "<promise pending>"
+	}
+	return "<promise " + string(state) + ": " + value.Inspect() + ">"
+}
+
+func (p *Promise) State() (PromiseState, Object) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state, p.value
+}
+
+// 确定结果，返回等待该结果的回调；已经确定过时返回false
+func (p *Promise) Settle(state PromiseState, value Object) ([]func(), bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state != PROMISE_PENDING {
+		return nil, false
+	}
+	p.state, p.value = state, value
+
+	callbacks := p.callbacks
+	p.callbacks = nil
+	p.abandoned = nil
+	return callbacks, true
+}
+
+// 注册结果确定后执行的回调；已经确定时不注册并返回false，由调用方安排执行
+func (p *Promise) OnSettled(callback func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state != PROMISE_PENDING {
+		return false
+	}
+	p.callbacks = append(p.callbacks, callback)
+	return true
+}
+
+// 注册promise被放弃时执行的回调；已经确定时不注册
+func (p *Promise) OnAbandoned(callback func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state == PROMISE_PENDING {
+		p.abandoned = append(p.abandoned, callback)
+	}
+}
+
+// 放弃仍为pending的promise，返回注册的回调，每个回调只会被返回一次
+// promise保持pending，放弃只用于释放等待它的一方
+func (p *Promise) Abandon() []func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	callbacks := p.abandoned
+	p.abandoned = nil
+	return callbacks
+}
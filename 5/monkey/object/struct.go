@@ -0,0 +1,39 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// 结构体类型，调用时按字段顺序或字段名构造实例
+type Struct struct {
+	Name    string
+	Fields  []string
+	Methods map[string]*Function // 第一个参数为实例self
+}
+
+func (s *Struct) Type() ObjectType { return STRUCT_OBJ }
+func (s *Struct) Inspect() string  { return "<struct " + s.Name + ">" }
+
+// 结构体实例，字段在构造后不可修改
+type Instance struct {
+	Struct *Struct
+	Fields map[string]Object
+}
+
+func (i *Instance) Type() ObjectType { return INSTANCE_OBJ }
+func (i *Instance) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, name := range i.Struct.Fields {
+		fields = append(fields, name+": "+i.Fields[name].Inspect())
+	}
+
+	out.WriteString(i.Struct.Name)
+	out.WriteString("(")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
@@ -12,13 +12,16 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	NULLISH     // a ?? b
 	EQUALS      // == !=
 	LESSGREATER // > or <
+	PIPELINE    // x |> f()
 	SUM         // + -
 	PRODUCT     // * /
 	PREFIX      // -X or !X
 	CALL        // myFunction(X)
 	INDEX       // array[index]
+	MEMBER      // object.property
 )
 
 // 词法单元运算符优先级
@@ -28,12 +31,18 @@ var precedences = map[token.TokenType]int{
 	token.NOT_EQ:   EQUALS,      // !=
 	token.LT:       LESSGREATER, // <
 	token.GT:       LESSGREATER, // >
+	token.PIPELINE: PIPELINE,    // |>
 	token.PLUS:     SUM,         // +
 	token.MINUS:    SUM,         // -
 	token.SLASH:    PRODUCT,     // /
 	token.ASTERISK: PRODUCT,     // *
 	token.LPAREN:   CALL,        // (
 	token.LBRACKET: INDEX,       // [
+	token.DOT:      MEMBER,      // .
+	token.NULLISH:  NULLISH,     // ??
+
+	token.OPTIONAL_DOT:     MEMBER, // ?.
+	token.OPTIONAL_BRACKET: INDEX,  // ?[
 }
 
 type (
@@ -50,6 +59,8 @@ type Parser struct {
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	functions []*ast.FunctionLiteral // 正在解析函数体的各层函数，最后一个为最内层
 }
 
 func New(l *lexer.Lexer) *Parser {
@@ -67,12 +78,21 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression) // -
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression) // (，分组表达式(1 + 2) * 3
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral) // fn
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)    // [，数组字面量[1+2, 3*4]
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)       // {，哈希字面量{"one" : 1 + 2, "two" : 2}
 	p.registerPrefix(token.MACRO, p.parseMacroLiteral)       // macro
+	p.registerPrefix(token.TRY, p.parseTryExpression)        // try
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)    // match
+	p.registerPrefix(token.FOR, p.parseForExpression)        // for
+	p.registerPrefix(token.SELECT, p.parseSelectExpression)  // select
+	p.registerPrefix(token.SWITCH, p.parseSwitchExpression)  // switch
+	p.registerPrefix(token.ASYNC, p.parseAsyncFunctionLiteral)
+	p.registerPrefix(token.AWAIT, p.parseAwaitExpression)
+	p.registerPrefix(token.BAR, p.parseLambdaLiteral) // |，简写函数|x| x * 2
 
 	//注册中缀解析函数
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
@@ -87,6 +107,11 @@ func New(l *lexer.Lexer) *Parser {
 
 	p.registerInfix(token.LPAREN, p.parseCallExpression)    // (，函数调用表达式add(2, 3)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression) // [，索引表达式a[0]
+	p.registerInfix(token.DOT, p.parseMemberExpression)     // .，成员访问表达式p.x
+	p.registerInfix(token.PIPELINE, p.parsePipelineExpression)
+	p.registerInfix(token.NULLISH, p.parseInfixExpression)          // ??，左侧为null时取右侧的值
+	p.registerInfix(token.OPTIONAL_DOT, p.parseMemberExpression)    // ?.，a?.b
+	p.registerInfix(token.OPTIONAL_BRACKET, p.parseIndexExpression) // ?[，a?[k]
 
 	// 读取当前词法单元和下一个词法单元
 	p.nextToken()
@@ -156,25 +181,71 @@ func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.SEMICOLON: //空语句;
 		return nil
-	case token.LET: //let语句
+	case token.LET, token.CONST: //let语句、const语句
 		return p.parseLetStatement()
 	case token.RETURN: //return语句
 		return p.parseReturnStatement()
+	case token.THROW: //throw语句
+		return p.parseThrowStatement()
+	case token.YIELD: //yield语句
+		return p.parseYieldStatement()
+	case token.FUNCTION: //函数声明fn name() {}，等价于let name = fn name() {}
+		if p.peekTokenIs(token.IDENT) {
+			return p.parseFunctionDeclaration()
+		}
+		return p.parseExpressionStatement()
+	case token.ASYNC: //async函数声明async fn name() {}
+		return p.parseAsyncFunctionStatement()
+	case token.STRUCT: //结构体声明
+		return p.parseStructStatement()
+	case token.ENUM: //枚举声明
+		return p.parseEnumStatement()
 	default: //expression语句
 		return p.parseExpressionStatement()
 	}
 }
 
-// 解析let语句（末尾可以无分号;）
+// 解析let语句和const语句（末尾可以无分号;）
+// let x = 1; let [a, ...rest] = arr; let {name, age} = person; const max = 10;
 func (p *Parser) parseLetStatement() *ast.LetStatement {
-	stmt := &ast.LetStatement{}
+	stmt := &ast.LetStatement{Const: p.curTokenIs(token.CONST)}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
+	switch {
+	case p.peekTokenIs(token.LBRACKET):
+		p.nextToken()
+		stmt.Pattern = p.parseArrayPattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	case p.peekTokenIs(token.LBRACE):
+		p.nextToken()
+		stmt.Pattern = p.parseHashPattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	case p.peekTokenIs(token.IDENT) && p.peekToken.Literal == "unquote":
+		// quote中的let unquote(name) = ...，绑定的名称在unquote求值后确定
+		p.nextToken()
+		switch target := p.parseExpression(PREFIX).(type) {
+		case *ast.Identifier:
+			stmt.Name = target
+		case *ast.CallExpression:
+			if len(target.Arguments) != 1 {
+				p.errors = append(p.errors, "expected unquote(name) in let statement")
+				return nil
+			}
+			stmt.Pattern = target
+		default:
+			p.errors = append(p.errors, "expected unquote(name) in let statement")
+			return nil
+		}
+	default:
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{Token: p.curToken}
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken}
-
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
@@ -190,6 +261,195 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	return stmt
 }
 
+// 解析函数声明（末尾可以无分号;）
+func (p *Parser) parseFunctionDeclaration() ast.Statement {
+	lit, ok := p.parseFunctionLiteral().(*ast.FunctionLiteral)
+	if !ok {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return &ast.LetStatement{Name: lit.Name, Value: lit}
+}
+
+// 解析以async开头的语句
+// 带名称的async函数字面量作为声明，等价于let name = async fn name() {}
+func (p *Parser) parseAsyncFunctionStatement() ast.Statement {
+	stmt := p.parseExpressionStatement()
+
+	if lit, ok := stmt.Expression.(*ast.FunctionLiteral); ok && lit.Name != nil {
+		return &ast.LetStatement{Name: lit.Name, Value: lit}
+	}
+
+	return stmt
+}
+
+// 解析结构体声明（末尾可以无分号;）
+// struct Point { x, y, fn norm(self) { ... } }
+// 字段和方法之间用逗号分隔，方法必须有名称，第一个参数为实例self
+func (p *Parser) parseStructStatement() ast.Statement {
+	stmt := &ast.StructStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	members := make(map[string]bool)
+	addMember := func(name *ast.Identifier) bool {
+		if members[name.Token.Literal] {
+			msg := fmt.Sprintf("duplicate member %s in struct %s", name, stmt.Name)
+			p.errors = append(p.errors, msg)
+			return false
+		}
+		members[name.Token.Literal] = true
+		return true
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		switch p.curToken.Type {
+		case token.IDENT:
+			field := &ast.Identifier{Token: p.curToken}
+			if !addMember(field) {
+				return nil
+			}
+			stmt.Fields = append(stmt.Fields, field)
+		case token.FUNCTION:
+			method, ok := p.parseFunctionLiteral().(*ast.FunctionLiteral)
+			if !ok {
+				return nil
+			}
+			if method.Name == nil {
+				msg := fmt.Sprintf("method in struct %s must have a name", stmt.Name)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			if len(method.Parameters) == 0 {
+				msg := fmt.Sprintf("method %s must take self as its first parameter", method.Name)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+			if !addMember(method.Name) {
+				return nil
+			}
+			stmt.Methods = append(stmt.Methods, method)
+		default:
+			msg := fmt.Sprintf("expected field or method in struct, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// 解析枚举声明（末尾可以无分号;）
+// enum Shape { Circle(r), Rect(w, h), Empty }
+func (p *Parser) parseEnumStatement() ast.Statement {
+	stmt := &ast.EnumStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	variants := make(map[string]bool)
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		if !p.curTokenIs(token.IDENT) {
+			msg := fmt.Sprintf("expected variant in enum, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		variant := &ast.EnumVariant{Name: &ast.Identifier{Token: p.curToken}}
+		if variants[variant.Name.Token.Literal] {
+			msg := fmt.Sprintf("duplicate variant %s in enum %s", variant.Name, stmt.Name)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		variants[variant.Name.Token.Literal] = true
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			variant.Fields = p.parseVariantFields(variant.Name)
+			if variant.Fields == nil {
+				return nil
+			}
+		}
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// 解析变体的字段列表，出错时返回nil
+func (p *Parser) parseVariantFields(variant *ast.Identifier) []*ast.Identifier {
+	fields := []*ast.Identifier{}
+	names := make(map[string]bool)
+
+	for !p.peekTokenIs(token.RPAREN) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		field := &ast.Identifier{Token: p.curToken}
+		if names[field.Token.Literal] {
+			msg := fmt.Sprintf("duplicate field %s in variant %s", field, variant)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		names[field.Token.Literal] = true
+		fields = append(fields, field)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return fields
+}
+
 // 解析return语句（末尾可以无分号;）
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{}
@@ -205,6 +465,43 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+// 解析yield语句（末尾可以无分号;）
+// 所在的函数因此成为生成器函数
+func (p *Parser) parseYieldStatement() *ast.YieldStatement {
+	stmt := &ast.YieldStatement{Token: p.curToken}
+
+	if len(p.functions) == 0 {
+		p.errors = append(p.errors, "yield outside function")
+	} else {
+		p.functions[len(p.functions)-1].Generator = true
+	}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// 解析throw语句（末尾可以无分号;）
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 // 解析expression语句（末尾可以无分号;）
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	//defer untrace(trace("parseExpressionStatement"))
@@ -236,7 +533,10 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
-			return leftExp
+			return closeOptionalChain(leftExp)
+		}
+		if !chainTokens[p.peekToken.Type] {
+			leftExp = closeOptionalChain(leftExp)
 		}
 
 		p.nextToken()
@@ -244,7 +544,38 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		leftExp = infix(leftExp) //调用中缀解析函数，leftExp作为参数传入
 	}
 
-	return leftExp
+	return closeOptionalChain(leftExp)
+}
+
+// 成员访问、索引和调用连在一起组成一条链
+var chainTokens = map[token.TokenType]bool{
+	token.DOT:              true,
+	token.OPTIONAL_DOT:     true,
+	token.LBRACKET:         true,
+	token.OPTIONAL_BRACKET: true,
+	token.LPAREN:           true,
+}
+
+// 链结束时，链中有?.或?[的把整条链包装为可选链，使null跳过链的其余部分
+func closeOptionalChain(exp ast.Expression) ast.Expression {
+	for link := exp; ; {
+		switch node := link.(type) {
+		case *ast.MemberExpression:
+			if node.Optional {
+				return &ast.OptionalChain{Expression: exp}
+			}
+			link = node.Object
+		case *ast.IndexExpression:
+			if node.Optional {
+				return &ast.OptionalChain{Expression: exp}
+			}
+			link = node.Left
+		case *ast.CallExpression:
+			link = node.Function
+		default:
+			return exp
+		}
+	}
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -330,6 +661,10 @@ func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
 
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 // 解析分组表达式
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
@@ -367,6 +702,19 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
 
+		// else if，将后续的if表达式包装为只含一条语句的else块
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			nested := p.parseIfExpression()
+			if nested == nil {
+				return nil
+			}
+			expression.Alternative = &ast.BlockStatement{
+				Statements: []ast.Statement{&ast.ExpressionStatement{Expression: nested}},
+			}
+			return expression
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			return nil
 		}
@@ -377,6 +725,223 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+// 解析for-in表达式
+// for (x in collection) { ... }、for (k, v in hash) { ... }
+func (p *Parser) parseForExpression() ast.Expression {
+	expression := &ast.ForExpression{}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	expression.Value = &ast.Identifier{Token: p.curToken}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		expression.Key = expression.Value
+		expression.Value = &ast.Identifier{Token: p.curToken}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Body = p.parseBlockStatement()
+
+	return expression
+}
+
+// 解析select表达式
+// select { case let v = recv(ch): ... case send(ch, x): ... default: ... }
+func (p *Parser) parseSelectExpression() ast.Expression {
+	expression := &ast.SelectExpression{}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) {
+		switch p.curToken.Type {
+		case token.CASE:
+			c := p.parseSelectCase()
+			if c == nil {
+				return nil
+			}
+			expression.Cases = append(expression.Cases, c)
+		case token.DEFAULT:
+			if expression.Default != nil {
+				p.errors = append(p.errors, "multiple defaults in select")
+				return nil
+			}
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			expression.Default = p.parseCaseBody()
+		default:
+			msg := fmt.Sprintf("expected case or default in select, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+	}
+
+	return expression
+}
+
+// 解析switch表达式
+// switch (x) { case 1, 2: ... default: ... }
+func (p *Parser) parseSwitchExpression() ast.Expression {
+	expression := &ast.SwitchExpression{}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) {
+		switch p.curToken.Type {
+		case token.CASE:
+			c := &ast.SwitchCase{}
+			for {
+				p.nextToken()
+				c.Values = append(c.Values, p.parseExpression(LOWEST))
+				if !p.peekTokenIs(token.COMMA) {
+					break
+				}
+				p.nextToken()
+			}
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			c.Body = p.parseCaseBody()
+			expression.Cases = append(expression.Cases, c)
+		case token.DEFAULT:
+			if expression.Default != nil {
+				p.errors = append(p.errors, "multiple defaults in switch")
+				return nil
+			}
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			expression.Default = p.parseCaseBody()
+		default:
+			msg := fmt.Sprintf("expected case or default in switch, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+	}
+
+	return expression
+}
+
+// 解析select分支
+// case let v = recv(ch): ...、case recv(ch): ...、case send(ch, x): ...
+func (p *Parser) parseSelectCase() *ast.SelectCase {
+	c := &ast.SelectCase{}
+
+	if p.peekTokenIs(token.LET) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		c.Name = &ast.Identifier{Token: p.curToken}
+		if !p.expectPeek(token.ASSIGN) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	call, ok := p.parseExpression(LOWEST).(*ast.CallExpression)
+	if !ok || !isSelectCall(call) {
+		p.errors = append(p.errors, "select case must be recv(ch) or send(ch, value)")
+		return nil
+	}
+	if c.Name != nil && call.Function.String() != "recv" {
+		p.errors = append(p.errors, "only recv(ch) can be bound in select case")
+		return nil
+	}
+	c.Call = call
+
+	if !p.expectPeek(token.COLON) {
+		return nil
+	}
+	c.Body = p.parseCaseBody()
+
+	return c
+}
+
+func isSelectCall(call *ast.CallExpression) bool {
+	if len(call.KeywordArguments) > 0 {
+		return false
+	}
+	switch call.Function.String() {
+	case "recv":
+		return len(call.Arguments) == 1
+	case "send":
+		return len(call.Arguments) == 2
+	default:
+		return false
+	}
+}
+
+// 解析case或default之后的语句，直到下一个case、default或右大括号}
+// 结束时当前token为下一个case、default、}或EOF
+func (p *Parser) parseCaseBody() *ast.BlockStatement {
+	block := &ast.BlockStatement{}
+	block.Statements = []ast.Statement{}
+
+	p.nextToken()
+
+	for !p.curTokenIs(token.CASE) && !p.curTokenIs(token.DEFAULT) &&
+		!p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	return block
+}
+
+// 解析await表达式
+// 可以出现在async函数或顶层代码中，不能出现在普通函数中
+func (p *Parser) parseAwaitExpression() ast.Expression {
+	expression := &ast.AwaitExpression{Token: p.curToken}
+
+	if len(p.functions) > 0 && !p.functions[len(p.functions)-1].Async {
+		p.errors = append(p.errors, "await outside async function")
+	}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(PREFIX)
+
+	return expression
+}
+
 // 解析block语句
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{}
@@ -396,27 +961,141 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 }
 
 // 解析函数字面量表达式
-// fn() {}
+// fn() {}、fn name() {}
 func (p *Parser) parseFunctionLiteral() ast.Expression {
-	lit := &ast.FunctionLiteral{}
+	return p.parseFunction(&ast.FunctionLiteral{})
+}
+
+// 解析简写函数字面量
+// |x, y| x + y、|| 1、|x| { let y = x * 2; y }
+// 函数体是一个表达式或块语句，参数列表为||时无参数
+func (p *Parser) parseLambdaLiteral() ast.Expression {
+	lit := &ast.FunctionLiteral{Parameters: []*ast.Identifier{}}
+
+	for !p.peekTokenIs(token.BAR) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		lit.Parameters = append(lit.Parameters, &ast.Identifier{Token: p.curToken})
+
+		if !p.peekTokenIs(token.BAR) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+	p.nextToken()
+
+	p.functions = append(p.functions, lit)
+	if p.curTokenIs(token.LBRACE) {
+		lit.Body = p.parseBlockStatement()
+	} else {
+		stmt := &ast.ExpressionStatement{Expression: p.parseExpression(LOWEST)}
+		lit.Body = &ast.BlockStatement{Statements: []ast.Statement{stmt}}
+	}
+	p.functions = p.functions[:len(p.functions)-1]
+
+	return lit
+}
+
+// 解析async函数字面量
+// async fn() {}、async fn name() {}
+func (p *Parser) parseAsyncFunctionLiteral() ast.Expression {
+	if !p.expectPeek(token.FUNCTION) {
+		return nil
+	}
+	return p.parseFunction(&ast.FunctionLiteral{Async: true})
+}
+
+func (p *Parser) parseFunction(lit *ast.FunctionLiteral) ast.Expression {
+
+	if p.peekTokenIs(token.IDENT) {
+		p.nextToken()
+		lit.Name = &ast.Identifier{Token: p.curToken}
+	}
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	if !p.parseFunctionLiteralParameters(lit) {
+		return nil
+	}
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	p.functions = append(p.functions, lit)
 	lit.Body = p.parseBlockStatement()
+	p.functions = p.functions[:len(p.functions)-1]
+
+	if lit.Async && lit.Generator {
+		p.errors = append(p.errors, "yield in async function is not supported")
+		return nil
+	}
 
 	return lit
 }
 
-// 解析函数字面量表达式，内部参数标识符a, b, c等
-// fn(a, b, c) {}
+// 解析函数字面量参数，支持默认值和剩余参数
+// fn(a, b = 2, ...rest) {}
+// 有默认值的参数之后不能再出现无默认值的参数，剩余参数只能位于最后
+func (p *Parser) parseFunctionLiteralParameters(lit *ast.FunctionLiteral) bool {
+	lit.Parameters = []*ast.Identifier{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return true
+	}
+
+	for {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return false
+			}
+			lit.Rest = &ast.Identifier{Token: p.curToken}
+			break
+		}
+
+		if !p.curTokenIs(token.IDENT) {
+			msg := fmt.Sprintf("expected parameter name, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return false
+		}
+		ident := &ast.Identifier{Token: p.curToken}
+
+		var defaultValue ast.Expression
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			defaultValue = p.parseExpression(LOWEST)
+			if lit.Defaults == nil {
+				lit.Defaults = make([]ast.Expression, len(lit.Parameters))
+			}
+		} else if lit.Defaults != nil {
+			msg := fmt.Sprintf("parameter %s without default follows parameter with default", ident.String())
+			p.errors = append(p.errors, msg)
+			return false
+		}
+
+		lit.Parameters = append(lit.Parameters, ident)
+		if lit.Defaults != nil {
+			lit.Defaults = append(lit.Defaults, defaultValue)
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	return p.expectPeek(token.RPAREN)
+}
+
+// 解析宏字面量参数，内部参数标识符a, b, c等
+// macro(a, b, c) {}
 func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	identifiers := []*ast.Identifier{}
 
@@ -445,13 +1124,57 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 }
 
 // 解析调用函数表达式
-// add(2, 3)
+// add(2, 3)、open(path, mode: "r")
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
-	exp := &ast.CallExpression{Function: function}
-	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	exp := &ast.CallExpression{Token: p.curToken, Function: function}
+	if !p.parseCallArguments(exp) {
+		return nil
+	}
 	return exp
 }
 
+// 解析调用实参，name: value形式为关键字参数，必须位于位置参数之后且不能重复
+func (p *Parser) parseCallArguments(exp *ast.CallExpression) bool {
+	exp.Arguments = []ast.Expression{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return true
+	}
+
+	seen := make(map[string]bool)
+	for {
+		p.nextToken()
+
+		if p.curTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) {
+			name := &ast.Identifier{Token: p.curToken}
+			if seen[name.String()] {
+				msg := fmt.Sprintf("duplicate keyword argument %s", name.String())
+				p.errors = append(p.errors, msg)
+				return false
+			}
+			seen[name.String()] = true
+
+			p.nextToken()
+			p.nextToken()
+			ka := &ast.KeywordArgument{Name: name, Value: p.parseExpression(LOWEST)}
+			exp.KeywordArguments = append(exp.KeywordArguments, ka)
+		} else if len(exp.KeywordArguments) > 0 {
+			p.errors = append(p.errors, "positional argument follows keyword argument")
+			return false
+		} else {
+			exp.Arguments = append(exp.Arguments, p.parseExpression(LOWEST))
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	return p.expectPeek(token.RPAREN)
+}
+
 // 解析表达式列表
 // add(2+3, minute(5, 3))
 // [1+2, 3]
@@ -490,7 +1213,7 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 
 // 解析索引表达式
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Left: left}
+	exp := &ast.IndexExpression{Left: left, Optional: p.curTokenIs(token.OPTIONAL_BRACKET)}
 
 	p.nextToken()
 	exp.Index = p.parseExpression(LOWEST)
@@ -502,6 +1225,48 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+// 解析管道表达式，转换为调用表达式
+// x |> f(a)等价于f(x, a)，x |> f等价于f(x)
+func (p *Parser) parsePipelineExpression(left ast.Expression) ast.Expression {
+	tok := p.curToken
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	right := p.parseExpression(precedence)
+	if right == nil {
+		return nil
+	}
+
+	call, ok := right.(*ast.CallExpression)
+	if chain, isChain := right.(*ast.OptionalChain); isChain {
+		call, ok = chain.Expression.(*ast.CallExpression)
+	}
+	if ok {
+		call.Arguments = append([]ast.Expression{left}, call.Arguments...)
+		return right
+	}
+
+	return &ast.CallExpression{Token: tok, Function: right, Arguments: []ast.Expression{left}}
+}
+
+// 解析成员访问表达式
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: object, Optional: p.curTokenIs(token.OPTIONAL_DOT)}
+
+	// 点号后的match是属性名，如re.match(s)
+	if p.peekTokenIs(token.MATCH) {
+		p.nextToken()
+		exp.Property = matchIdentifier(p.curToken)
+		return exp
+	}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	exp.Property = &ast.Identifier{Token: p.curToken}
+
+	return exp
+}
+
 // 解析哈希字面量表达式
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{}
@@ -547,10 +1312,285 @@ func (p *Parser) parseMacroLiteral() ast.Expression {
 		return nil
 	}
 
+	// 宏体中的yield和await不属于外层函数
+	p.functions = append(p.functions, &ast.FunctionLiteral{})
 	lit.Body = p.parseBlockStatement()
+	p.functions = p.functions[:len(p.functions)-1]
 
 	return lit
 }
+
+// 解析try表达式
+// try { } catch (e) { } finally { }
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			expression.CatchParam = &ast.Identifier{Token: p.curToken}
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		p.errors = append(p.errors, "expected catch or finally after try block")
+		return nil
+	}
+
+	return expression
+}
+
+// 作为标识符使用的match
+func matchIdentifier(tok token.Token) *ast.Identifier {
+	tok.Type = token.IDENT
+	return &ast.Identifier{Token: tok}
+}
+
+// 解析match表达式
+// match value { pattern => expr, pattern if guard => expr }
+// match是上下文关键字：后面紧跟(时是对内置函数match的调用，如match(re, s)，
+// 因此match表达式的值不能以括号开头
+func (p *Parser) parseMatchExpression() ast.Expression {
+	if p.peekTokenIs(token.LPAREN) {
+		return matchIdentifier(p.curToken)
+	}
+
+	expression := &ast.MatchExpression{}
+
+	p.nextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		arm := &ast.MatchArm{Pattern: p.parsePattern()}
+		if arm.Pattern == nil {
+			return nil
+		}
+
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			p.nextToken()
+			arm.Guard = p.parseExpression(LOWEST)
+		}
+
+		if !p.expectPeek(token.ARROW) {
+			return nil
+		}
+
+		p.nextToken()
+		arm.Body = p.parseExpression(LOWEST)
+
+		expression.Arms = append(expression.Arms, arm)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return expression
+}
+
+// 解析模式
+// 字面量、标识符（绑定，_为通配）、数组模式、哈希模式和枚举变体模式
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.curToken.Type {
+	case token.IDENT:
+		if p.peekTokenIs(token.DOT) {
+			return p.parseVariantPattern()
+		}
+		return &ast.Identifier{Token: p.curToken}
+	case token.INT:
+		return p.parseIntegerLiteral()
+	case token.STRING:
+		return p.parseStringLiteral()
+	case token.TRUE, token.FALSE:
+		return p.parseBoolean()
+	case token.NULL:
+		return p.parseNullLiteral()
+	case token.MINUS: // 负整数
+		expression := &ast.PrefixExpression{Token: p.curToken}
+		if !p.expectPeek(token.INT) {
+			return nil
+		}
+		expression.Right = p.parseIntegerLiteral()
+		return expression
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	default:
+		msg := fmt.Sprintf("unexpected %s in pattern", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+}
+
+// 解析枚举变体模式
+// Shape.Circle(r)、Shape.Rect(w, 2)、Color.Red
+func (p *Parser) parseVariantPattern() ast.Expression {
+	pattern := &ast.VariantPattern{Enum: &ast.Identifier{Token: p.curToken}}
+
+	p.nextToken()
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	pattern.Variant = &ast.Identifier{Token: p.curToken}
+
+	if !p.peekTokenIs(token.LPAREN) {
+		return pattern
+	}
+	p.nextToken()
+
+	pattern.Fields = []ast.Expression{}
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+
+		field := p.parsePattern()
+		if field == nil {
+			return nil
+		}
+		pattern.Fields = append(pattern.Fields, field)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return pattern
+}
+
+// 解析数组模式
+// [a, [b, c], ...rest]
+func (p *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{Elements: []ast.Expression{}}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) { // 剩余元素，只能位于最后
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: p.curToken}
+			break
+		}
+
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+// 解析哈希模式
+// {"key": pattern, 1: pattern, name: pattern, name}
+// 标识符作为键时表示同名字符串键，单独的name等价于name: name
+func (p *Parser) parseHashPattern() ast.Expression {
+	pattern := &ast.HashPattern{}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		var key, value ast.Expression
+		switch p.curToken.Type {
+		case token.IDENT:
+			keyToken := p.curToken
+			keyToken.Type = token.STRING
+			key = &ast.StringLiteral{Token: keyToken}
+			value = &ast.Identifier{Token: p.curToken}
+		case token.STRING, token.INT, token.TRUE, token.FALSE, token.MINUS:
+			key = p.parsePattern()
+			if key == nil {
+				return nil
+			}
+			if !p.peekTokenIs(token.COLON) {
+				p.peekError(token.COLON)
+				return nil
+			}
+		default:
+			msg := fmt.Sprintf("unexpected %s in hash pattern key", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parsePattern()
+			if value == nil {
+				return nil
+			}
+		}
+
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
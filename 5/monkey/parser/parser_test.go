@@ -174,6 +174,109 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedIdentifier string
+		expectedValue      interface{}
+		expectedString     string
+	}{
+		{"const x = 5;", "x", 5, "const x = 5;\n"},
+		{"const max = y", "max", "y", "const max = y;\n"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0]
+		if !testLetStatement(t, stmt, tt.expectedIdentifier) {
+			return
+		}
+
+		letStmt := stmt.(*ast.LetStatement)
+		if !letStmt.Const {
+			t.Errorf("letStmt.Const is not true for %q", tt.input)
+		}
+		testLiteralExpression(t, letStmt.Value, tt.expectedValue)
+
+		if letStmt.String() != tt.expectedString {
+			t.Errorf("letStmt.String() wrong. want=%q, got=%q", tt.expectedString, letStmt.String())
+		}
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedPattern string
+		expectedValue   string
+	}{
+		{"let [a, b] = arr;", "[a, b]", "arr"},
+		{"let [first, ...rest] = [1, 2, 3];", "[first, ...rest]", "[1, 2, 3]"},
+		{"let [[a], _] = pairs", "[[a], _]", "pairs"},
+		{"let {name, age} = person;", "{name : name, age : age}", "person"},
+		{`let {"first name": first, nested: [x]} = person;`, "{first name : first, nested : [x]}", "person"},
+		{"let unquote(name) = 1;", "unquote(name)", "1"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statements. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.LetStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if stmt.Name != nil {
+			t.Errorf("stmt.Name is not nil. got=%q", stmt.Name.String())
+		}
+		if stmt.Pattern == nil || stmt.Pattern.String() != tt.expectedPattern {
+			t.Errorf("stmt.Pattern wrong. want=%q, got=%v", tt.expectedPattern, stmt.Pattern)
+		}
+		if stmt.Value.String() != tt.expectedValue {
+			t.Errorf("stmt.Value wrong. want=%q, got=%q", tt.expectedValue, stmt.Value.String())
+		}
+	}
+}
+
+func TestLetUnquoteStatements(t *testing.T) {
+	l := lexer.New("let unquote = 5;")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if !testLetStatement(t, program.Statements[0], "unquote") {
+		return
+	}
+
+	tests := []string{
+		"let unquote(a, b) = 1;",
+		"let unquote[0] = 1;",
+	}
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != "expected unquote(name) in let statement" {
+			t.Errorf("wrong parser errors for %q. got=%q", input, errors)
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -479,6 +582,38 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));\n",
 		},
+		{
+			"-p.x * q.y.z",
+			"((-(p.x)) * ((q.y).z));\n",
+		},
+		{
+			"a?.b?[c].d ?? e + 1 == f",
+			"((((a?.b)?[c]).d) ?? ((e + 1) == f));\n",
+		},
+		{
+			"a ?? b ?? null",
+			"((a ?? b) ?? null);\n",
+		},
+		{
+			"xs |> map(f) |> filter(g)",
+			"filter(map(xs, f), g);\n",
+		},
+		{
+			"a + b |> f < c * d |> g",
+			"(f((a + b)) < g((c * d)));\n",
+		},
+		{
+			"x |> p.m(1) |> |y| y",
+			"fn(y) {\n\ty;\n}((p.m)(x, 1));\n",
+		},
+		{
+			"a.b[0].c(d.e)",
+			"(((a.b)[0]).c)((d.e));\n",
+		},
+		{
+			"p.norm(1) + a[0].x",
+			"((p.norm)(1) + ((a[0]).x));\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -531,6 +666,22 @@ func TestBooleanExpression(t *testing.T) {
 	}
 }
 
+func TestNullLiteralExpression(t *testing.T) {
+	l := lexer.New("null;")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.NullLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.NullLiteral. got=%T", stmt.Expression)
+	}
+	if literal.String() != "null" {
+		t.Errorf("literal.String() not %q. got=%q", "null", literal.String())
+	}
+}
+
 func TestIfExpression(t *testing.T) {
 	input := `if (x < y) { x }`
 
@@ -639,61 +790,16 @@ func TestIfElseExpression(t *testing.T) {
 	}
 }
 
-func TestFunctionLiteralParsing(t *testing.T) {
-	input := `fn(x, y) { x + y; }`
-
-	l := lexer.New(input)
-	p := New(l)
-	program := p.ParseProgram()
-	checkParserErrors(t, p)
-
-	if len(program.Statements) != 1 {
-		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
-			1, len(program.Statements))
-	}
-
-	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
-	if !ok {
-		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
-			program.Statements[0])
-	}
-
-	function, ok := stmt.Expression.(*ast.FunctionLiteral)
-	if !ok {
-		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T",
-			stmt.Expression)
-	}
-
-	if len(function.Parameters) != 2 {
-		t.Fatalf("function literal parameters wrong. want 2, got=%d\n",
-			len(function.Parameters))
-	}
-
-	testLiteralExpression(t, function.Parameters[0], "x")
-	testLiteralExpression(t, function.Parameters[1], "y")
-
-	if len(function.Body.Statements) != 1 {
-		t.Fatalf("function.Body.Statements has not 1 statements. got=%d\n",
-			len(function.Body.Statements))
-	}
-
-	bodyStmt, ok := function.Body.Statements[0].(*ast.ExpressionStatement)
-	if !ok {
-		t.Fatalf("function body stmt is not ast.ExpressionStatement. got=%T",
-			function.Body.Statements[0])
-	}
-
-	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
-}
-
-func TestFunctionParameterParsing(t *testing.T) {
+func TestForExpression(t *testing.T) {
 	tests := []struct {
-		input          string
-		expectedParams []string
+		input         string
+		expectedKey   string
+		expectedValue string
+		expected      string
 	}{
-		{input: "fn() {};", expectedParams: []string{}},
-		{input: "fn(x) {};", expectedParams: []string{"x"}},
-		{input: "fn(x, y, z) {};", expectedParams: []string{"x", "y", "z"}},
+		{"for (x in xs) { x }", "", "x", "for (x in xs) {\n\tx;\n}"},
+		{"for (k, v in h) { k + v }", "k", "v", "for (k, v in h) {\n\t(k + v);\n}"},
+		{"for (i in range(1, 10)) { }", "", "i", "for (i in range(1, 10)) {\n}"},
 	}
 
 	for _, tt := range tests {
@@ -703,78 +809,58 @@ func TestFunctionParameterParsing(t *testing.T) {
 		checkParserErrors(t, p)
 
 		stmt := program.Statements[0].(*ast.ExpressionStatement)
-		function := stmt.Expression.(*ast.FunctionLiteral)
+		exp, ok := stmt.Expression.(*ast.ForExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.ForExpression. got=%T",
+				stmt.Expression)
+		}
 
-		if len(function.Parameters) != len(tt.expectedParams) {
-			t.Errorf("length parameters wrong. want %d, got=%d\n",
-				len(tt.expectedParams), len(function.Parameters))
+		if tt.expectedKey == "" {
+			if exp.Key != nil {
+				t.Errorf("exp.Key was not nil. got=%+v", exp.Key)
+			}
+		} else {
+			testIdentifier(t, exp.Key, tt.expectedKey)
 		}
+		testIdentifier(t, exp.Value, tt.expectedValue)
 
-		for i, ident := range tt.expectedParams {
-			testLiteralExpression(t, function.Parameters[i], ident)
+		if exp.String() != tt.expected {
+			t.Errorf("exp.String() wrong. want=%q, got=%q", tt.expected, exp.String())
 		}
 	}
 }
 
-func TestCallExpressionParsing(t *testing.T) {
-	input := "add(1, 2 * 3, 4 + 5);"
-
-	l := lexer.New(input)
-	p := New(l)
-	program := p.ParseProgram()
-	checkParserErrors(t, p)
-
-	if len(program.Statements) != 1 {
-		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
-			1, len(program.Statements))
-	}
-
-	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
-	if !ok {
-		t.Fatalf("stmt is not ast.ExpressionStatement. got=%T",
-			program.Statements[0])
-	}
-
-	exp, ok := stmt.Expression.(*ast.CallExpression)
-	if !ok {
-		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T",
-			stmt.Expression)
+func TestForExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x of xs) { x }", "expected next token to be IN, got IDENT instead"},
+		{"for (1 in xs) { x }", "expected next token to be IDENT, got INT instead"},
+		{"for (x in xs) x", "expected next token to be {, got IDENT instead"},
 	}
 
-	if !testIdentifier(t, exp.Function, "add") {
-		return
-	}
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
 
-	if len(exp.Arguments) != 3 {
-		t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
 	}
-
-	testLiteralExpression(t, exp.Arguments[0], 1)
-	testInfixExpression(t, exp.Arguments[1], 2, "*", 3)
-	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
 }
 
-func TestCallExpressionParameterParsing(t *testing.T) {
+func TestYieldStatement(t *testing.T) {
 	tests := []struct {
-		input         string
-		expectedIdent string
-		expectedArgs  []string
+		input     string
+		generator bool
 	}{
-		{
-			input:         "add();",
-			expectedIdent: "add",
-			expectedArgs:  []string{},
-		},
-		{
-			input:         "add(1);",
-			expectedIdent: "add",
-			expectedArgs:  []string{"1"},
-		},
-		{
-			input:         "add(1, 2 * 3, 4 + 5);",
-			expectedIdent: "add",
-			expectedArgs:  []string{"1", "(2 * 3)", "(4 + 5)"},
-		},
+		{"fn() { yield 1; }", true},
+		{"fn() { if (x) { yield x } }", true},
+		{"fn() { fn() { yield 1 } }", false},
+		{"fn() { 1 }", false},
 	}
 
 	for _, tt := range tests {
@@ -784,32 +870,28 @@ func TestCallExpressionParameterParsing(t *testing.T) {
 		checkParserErrors(t, p)
 
 		stmt := program.Statements[0].(*ast.ExpressionStatement)
-		exp, ok := stmt.Expression.(*ast.CallExpression)
+		fn, ok := stmt.Expression.(*ast.FunctionLiteral)
 		if !ok {
-			t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T",
+			t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T",
 				stmt.Expression)
 		}
-
-		if !testIdentifier(t, exp.Function, tt.expectedIdent) {
-			return
+		if fn.Generator != tt.generator {
+			t.Errorf("fn.Generator wrong for %q. want=%t, got=%t", tt.input, tt.generator, fn.Generator)
 		}
+	}
 
-		if len(exp.Arguments) != len(tt.expectedArgs) {
-			t.Fatalf("wrong number of arguments. want=%d, got=%d",
-				len(tt.expectedArgs), len(exp.Arguments))
-		}
+	l := lexer.New("yield 1;")
+	p := New(l)
+	p.ParseProgram()
 
-		for i, arg := range tt.expectedArgs {
-			if exp.Arguments[i].String() != arg {
-				t.Errorf("argument %d wrong. want=%q, got=%q", i,
-					arg, exp.Arguments[i].String())
-			}
-		}
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "yield outside function" {
+		t.Errorf("wrong parser errors. got=%q", errors)
 	}
 }
 
-func TestStringLiteralExpression(t *testing.T) {
-	input := `"hello world";`
+func TestElseIfExpression(t *testing.T) {
+	input := `if (x < y) { x } else if (x > y) { y } else { 0 }`
 
 	l := lexer.New(input)
 	p := New(l)
@@ -817,36 +899,676 @@ func TestStringLiteralExpression(t *testing.T) {
 	checkParserErrors(t, p)
 
 	stmt := program.Statements[0].(*ast.ExpressionStatement)
-	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	exp, ok := stmt.Expression.(*ast.IfExpression)
 	if !ok {
-		t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", stmt.Expression)
 	}
 
-	if literal.Token.Literal != "hello world" {
-		t.Errorf("literal.Token.Literal not %q. got=%q", "hello world", literal.Token.Literal)
+	if exp.Alternative == nil || len(exp.Alternative.Statements) != 1 {
+		t.Fatalf("exp.Alternative does not contain 1 statement. got=%+v", exp.Alternative)
+	}
+	alternative := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+	nested, ok := alternative.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("alternative is not ast.IfExpression. got=%T", alternative.Expression)
+	}
+	if !testInfixExpression(t, nested.Condition, "x", ">", "y") {
+		return
+	}
+	if nested.Alternative == nil || nested.Alternative.String() != "{\n\t0;\n}" {
+		t.Errorf("nested alternative wrong. got=%+v", nested.Alternative)
 	}
 }
 
-func TestParsingEmptyArrayLiterals(t *testing.T) {
-	input := "[]"
+func TestSwitchExpression(t *testing.T) {
+	input := `switch (x) {
+case 1, 2:
+	let y = x * 2;
+	y
+case "a":
+	"letter"
+default:
+	"other"
+}`
 
 	l := lexer.New(input)
 	p := New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
 
-	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
-	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.SwitchExpression)
 	if !ok {
-		t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+		t.Fatalf("stmt.Expression is not ast.SwitchExpression. got=%T",
+			stmt.Expression)
 	}
 
-	if len(array.Elements) != 0 {
-		t.Errorf("len(array.Elements) not 0. got=%d", len(array.Elements))
+	testIdentifier(t, exp.Subject, "x")
+	if len(exp.Cases) != 2 {
+		t.Fatalf("wrong number of cases. want=2, got=%d", len(exp.Cases))
 	}
-}
-
-func TestParsingArrayLiterals(t *testing.T) {
+	if len(exp.Cases[0].Values) != 2 {
+		t.Fatalf("case 0 has wrong number of values. got=%d", len(exp.Cases[0].Values))
+	}
+	testLiteralExpression(t, exp.Cases[0].Values[0], 1)
+	testLiteralExpression(t, exp.Cases[0].Values[1], 2)
+	if len(exp.Cases[0].Body.Statements) != 2 {
+		t.Errorf("case 0 body has wrong number of statements. got=%d",
+			len(exp.Cases[0].Body.Statements))
+	}
+	if len(exp.Cases[1].Values) != 1 || exp.Cases[1].Values[0].String() != "a" {
+		t.Errorf("case 1 values wrong. got=%+v", exp.Cases[1].Values)
+	}
+	if exp.Default == nil || len(exp.Default.Statements) != 1 {
+		t.Errorf("default wrong. got=%+v", exp.Default)
+	}
+}
+
+func TestSwitchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"switch x { case 1: 1 }", "expected next token to be (, got IDENT instead"},
+		{"switch (x) { default: 1 default: 2 }", "multiple defaults in switch"},
+		{"switch (x) { 1 }", "expected case or default in switch, got INT instead"},
+		{"switch (x) { case 1 2 }", "expected next token to be :, got INT instead"},
+		{"switch (x) { case 1: 1", "expected case or default in switch, got EOF instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestSelectExpression(t *testing.T) {
+	input := `select {
+case let v = recv(ch):
+	let x = v * 2;
+	x
+case send(out, 1):
+	"sent"
+default:
+	"idle"
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.SelectExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.SelectExpression. got=%T",
+			stmt.Expression)
+	}
+
+	if len(exp.Cases) != 2 {
+		t.Fatalf("wrong number of cases. want=2, got=%d", len(exp.Cases))
+	}
+	testIdentifier(t, exp.Cases[0].Name, "v")
+	if len(exp.Cases[0].Body.Statements) != 2 {
+		t.Errorf("case 0 body has wrong number of statements. got=%d",
+			len(exp.Cases[0].Body.Statements))
+	}
+	if exp.Cases[1].Name != nil {
+		t.Errorf("case 1 name was not nil. got=%+v", exp.Cases[1].Name)
+	}
+	if exp.Cases[1].Call.String() != "send(out, 1)" {
+		t.Errorf("case 1 call wrong. got=%q", exp.Cases[1].Call.String())
+	}
+	if exp.Default == nil || len(exp.Default.Statements) != 1 {
+		t.Errorf("default wrong. got=%+v", exp.Default)
+	}
+}
+
+func TestSelectExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"select { case f(ch): 1 }", "select case must be recv(ch) or send(ch, value)"},
+		{"select { case recv(a, b): 1 }", "select case must be recv(ch) or send(ch, value)"},
+		{"select { case let v = send(ch, 1): 1 }", "only recv(ch) can be bound in select case"},
+		{"select { default: 1 default: 2 }", "multiple defaults in select"},
+		{"select { 1 }", "expected case or default in select, got INT instead"},
+		{"select { case recv(ch): 1", "expected case or default in select, got EOF instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestAsyncFunctionParsing(t *testing.T) {
+	input := `
+let f = async fn(x) { await x + 1 };
+async fn load(url) { await fetch(url) }
+`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d",
+			len(program.Statements))
+	}
+
+	for i, name := range []string{"f", "load"} {
+		stmt, ok := program.Statements[i].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[%d] is not ast.LetStatement. got=%T",
+				i, program.Statements[i])
+		}
+		testIdentifier(t, stmt.Name, name)
+
+		fn, ok := stmt.Value.(*ast.FunctionLiteral)
+		if !ok || !fn.Async {
+			t.Fatalf("stmt.Value is not async function. got=%T (%+v)", stmt.Value, stmt.Value)
+		}
+		body := fn.Body.Statements[0].(*ast.ExpressionStatement)
+		if _, ok := body.Expression.(*ast.InfixExpression); i == 0 && !ok {
+			t.Errorf("await does not bind tighter than +. got=%q", body.Expression.String())
+		}
+	}
+
+	expected := "let f = async fn(x) {\n\t((await x) + 1);\n};\n"
+	if program.Statements[0].String() != expected {
+		t.Errorf("wrong String(). want=%q, got=%q", expected, program.Statements[0].String())
+	}
+}
+
+func TestAsyncFunctionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn() { await p }", "await outside async function"},
+		{"async fn() { fn() { await p } }", "await outside async function"},
+		{"async fn() { yield 1 }", "yield in async function is not supported"},
+		{"async 1", "expected next token to be FUNCTION, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestFunctionLiteralParsing(t *testing.T) {
+	input := `fn(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T",
+			stmt.Expression)
+	}
+
+	if len(function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. want 2, got=%d\n",
+			len(function.Parameters))
+	}
+
+	testLiteralExpression(t, function.Parameters[0], "x")
+	testLiteralExpression(t, function.Parameters[1], "y")
+
+	if len(function.Body.Statements) != 1 {
+		t.Fatalf("function.Body.Statements has not 1 statements. got=%d\n",
+			len(function.Body.Statements))
+	}
+
+	bodyStmt, ok := function.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("function body stmt is not ast.ExpressionStatement. got=%T",
+			function.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
+
+func TestLambdaLiteralParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+		expectedBody   string
+	}{
+		{"|x| x * 2", []string{"x"}, "(x * 2)"},
+		{"|a, b| a + b", []string{"a", "b"}, "(a + b)"},
+		{"|| 1", []string{}, "1"},
+		{"|x| { let y = x; y }", []string{"x"}, "let y = x;\n\ty"},
+		{"|x| |y| x + y", []string{"x"}, "fn(y) {\n\t(x + y);\n}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T",
+				stmt.Expression)
+		}
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Errorf("length parameters wrong. want %d, got=%d\n",
+				len(tt.expectedParams), len(function.Parameters))
+		}
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+		}
+
+		body := function.Body.String()
+		if want := "{\n\t" + tt.expectedBody + ";\n}"; body != want {
+			t.Errorf("body wrong for %q. want=%q, got=%q", tt.input, want, body)
+		}
+	}
+}
+
+func TestLambdaLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"|1| x", "expected next token to be IDENT, got INT instead"},
+		{"|x y| x", "expected next token to be ,, got IDENT instead"},
+		{"async fn() { |x| await x }", "await outside async function"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestFunctionParameterParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+	}{
+		{input: "fn() {};", expectedParams: []string{}},
+		{input: "fn(x) {};", expectedParams: []string{"x"}},
+		{input: "fn(x, y, z) {};", expectedParams: []string{"x", "y", "z"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmt.Expression.(*ast.FunctionLiteral)
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Errorf("length parameters wrong. want %d, got=%d\n",
+				len(tt.expectedParams), len(function.Parameters))
+		}
+
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+		}
+	}
+}
+
+func TestFunctionDefaultAndRestParameterParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedParams   []string
+		expectedDefaults []string
+		expectedRest     string
+		expectedString   string
+	}{
+		{"fn(a, b = 2) {}", []string{"a", "b"}, []string{"", "2"}, "", "fn(a, b = 2) {\n}"},
+		{"fn(a = 1, b = a + 1) {}", []string{"a", "b"}, []string{"1", "(a + 1)"}, "", "fn(a = 1, b = (a + 1)) {\n}"},
+		{"fn(...args) {}", []string{}, nil, "args", "fn(...args) {\n}"},
+		{"fn(first, b = 0, ...rest) {}", []string{"first", "b"}, []string{"", "0"}, "rest", "fn(first, b = 0, ...rest) {\n}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function := stmt.Expression.(*ast.FunctionLiteral)
+
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Fatalf("length parameters wrong. want %d, got=%d\n",
+				len(tt.expectedParams), len(function.Parameters))
+		}
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+		}
+
+		if tt.expectedDefaults == nil && function.Defaults != nil {
+			t.Errorf("function.Defaults is not nil. got=%+v", function.Defaults)
+		}
+		for i, expected := range tt.expectedDefaults {
+			got := ""
+			if function.Defaults[i] != nil {
+				got = function.Defaults[i].String()
+			}
+			if got != expected {
+				t.Errorf("default %d wrong. want=%q, got=%q", i, expected, got)
+			}
+		}
+
+		rest := ""
+		if function.Rest != nil {
+			rest = function.Rest.String()
+		}
+		if rest != tt.expectedRest {
+			t.Errorf("rest parameter wrong. want=%q, got=%q", tt.expectedRest, rest)
+		}
+
+		if function.String() != tt.expectedString {
+			t.Errorf("function.String() wrong. want=%q, got=%q", tt.expectedString, function.String())
+		}
+	}
+}
+
+func TestFunctionParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(a = 1, b) {}", "parameter b without default follows parameter with default"},
+		{"fn(...rest, a) {}", "expected next token to be ), got , instead"},
+		{"fn(1) {}", "expected parameter name, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestNamedFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedName   string
+		expectedString string
+	}{
+		{"let f = fn fact(n) { n };", "fact", "let f = fn fact(n) {\n\tn;\n};\n"},
+		{"fn add(a, b) { a + b }", "add", "let add = fn add(a, b) {\n\t(a + b);\n};\n"},
+		{"fn(x) { x }", "", "fn(x) {\n\tx;\n};\n"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statements. got=%d",
+				len(program.Statements))
+		}
+
+		var function *ast.FunctionLiteral
+		switch stmt := program.Statements[0].(type) {
+		case *ast.LetStatement:
+			function = stmt.Value.(*ast.FunctionLiteral)
+		case *ast.ExpressionStatement:
+			function = stmt.Expression.(*ast.FunctionLiteral)
+		}
+
+		name := ""
+		if function.Name != nil {
+			name = function.Name.String()
+		}
+		if name != tt.expectedName {
+			t.Errorf("function.Name wrong. want=%q, got=%q", tt.expectedName, name)
+		}
+
+		if program.String() != tt.expectedString {
+			t.Errorf("program.String() wrong. want=%q, got=%q", tt.expectedString, program.String())
+		}
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, 4 + 5);"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T",
+			stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Function, "add") {
+		return
+	}
+
+	if len(exp.Arguments) != 3 {
+		t.Fatalf("wrong length of arguments. got=%d", len(exp.Arguments))
+	}
+
+	testLiteralExpression(t, exp.Arguments[0], 1)
+	testInfixExpression(t, exp.Arguments[1], 2, "*", 3)
+	testInfixExpression(t, exp.Arguments[2], 4, "+", 5)
+}
+
+func TestCallExpressionParameterParsing(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedIdent string
+		expectedArgs  []string
+	}{
+		{
+			input:         "add();",
+			expectedIdent: "add",
+			expectedArgs:  []string{},
+		},
+		{
+			input:         "add(1);",
+			expectedIdent: "add",
+			expectedArgs:  []string{"1"},
+		},
+		{
+			input:         "add(1, 2 * 3, 4 + 5);",
+			expectedIdent: "add",
+			expectedArgs:  []string{"1", "(2 * 3)", "(4 + 5)"},
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.CallExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T",
+				stmt.Expression)
+		}
+
+		if !testIdentifier(t, exp.Function, tt.expectedIdent) {
+			return
+		}
+
+		if len(exp.Arguments) != len(tt.expectedArgs) {
+			t.Fatalf("wrong number of arguments. want=%d, got=%d",
+				len(tt.expectedArgs), len(exp.Arguments))
+		}
+
+		for i, arg := range tt.expectedArgs {
+			if exp.Arguments[i].String() != arg {
+				t.Errorf("argument %d wrong. want=%q, got=%q", i,
+					arg, exp.Arguments[i].String())
+			}
+		}
+	}
+}
+
+func TestCallExpressionKeywordArguments(t *testing.T) {
+	input := "open(path, mode: \"r\", buffer: 1 + 2);"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression. got=%T",
+			stmt.Expression)
+	}
+
+	if len(exp.Arguments) != 1 {
+		t.Fatalf("wrong number of arguments. want=1, got=%d", len(exp.Arguments))
+	}
+	testIdentifier(t, exp.Arguments[0], "path")
+
+	if len(exp.KeywordArguments) != 2 {
+		t.Fatalf("wrong number of keyword arguments. want=2, got=%d",
+			len(exp.KeywordArguments))
+	}
+	if exp.KeywordArguments[0].Name.String() != "mode" {
+		t.Errorf("keyword argument 0 name wrong. got=%q", exp.KeywordArguments[0].Name)
+	}
+	testInfixExpression(t, exp.KeywordArguments[1].Value, 1, "+", 2)
+
+	expected := "open(path, mode: r, buffer: (1 + 2))"
+	if exp.String() != expected {
+		t.Errorf("exp.String() wrong. want=%q, got=%q", expected, exp.String())
+	}
+}
+
+func TestCallExpressionKeywordArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(a: 1, a: 2)", "duplicate keyword argument a"},
+		{"f(a: 1, 2)", "positional argument follows keyword argument"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello world";`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.StringLiteral. got=%T", stmt.Expression)
+	}
+
+	if literal.Token.Literal != "hello world" {
+		t.Errorf("literal.Token.Literal not %q. got=%q", "hello world", literal.Token.Literal)
+	}
+}
+
+func TestParsingEmptyArrayLiterals(t *testing.T) {
+	input := "[]"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("exp not ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 0 {
+		t.Errorf("len(array.Elements) not 0. got=%d", len(array.Elements))
+	}
+}
+
+func TestParsingArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
 	l := lexer.New(input)
@@ -892,6 +1614,221 @@ func TestParsingIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestOptionalChainParsing(t *testing.T) {
+	input := "a?.b?[0]"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	chain, ok := stmt.Expression.(*ast.OptionalChain)
+	if !ok {
+		t.Fatalf("exp not *ast.OptionalChain. got=%T", stmt.Expression)
+	}
+	index, ok := chain.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("chain.Expression not *ast.IndexExpression. got=%T", chain.Expression)
+	}
+	if !index.Optional {
+		t.Errorf("index.Optional is false")
+	}
+
+	member, ok := index.Left.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("index.Left not *ast.MemberExpression. got=%T", index.Left)
+	}
+	if !member.Optional {
+		t.Errorf("member.Optional is false")
+	}
+	testIdentifier(t, member.Object, "a")
+	testIdentifier(t, member.Property, "b")
+}
+
+func TestOptionalChainExtent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string // 可选链包装的表达式，空字符串表示没有可选链
+	}{
+		{"a?.b.c", "((a?.b).c)"},
+		{"a?.m(1)", "(a?.m)(1)"},
+		{"a.b?[0].c", "(((a.b)?[0]).c)"},
+		{"a?.b + 1", "(a?.b)"},
+		{"-a?.b", ""},
+		{"(a?.b).c", ""},
+		{"a.b.c", ""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		chain := findOptionalChain(stmt.Expression)
+		switch {
+		case chain == nil && tt.expected != "":
+			t.Errorf("no optional chain in %q", tt.input)
+		case chain != nil && chain.Expression.String() != tt.expected:
+			t.Errorf("wrong optional chain in %q. want=%q, got=%q", tt.input, tt.expected, chain.Expression.String())
+		}
+	}
+}
+
+// 找到表达式顶层的可选链，穿过中缀表达式的左侧
+func findOptionalChain(exp ast.Expression) *ast.OptionalChain {
+	switch exp := exp.(type) {
+	case *ast.OptionalChain:
+		return exp
+	case *ast.InfixExpression:
+		return findOptionalChain(exp.Left)
+	default:
+		return nil
+	}
+}
+
+func TestParsingMemberExpressions(t *testing.T) {
+	input := "point.x"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.MemberExpression. got=%T", stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Object, "point") {
+		return
+	}
+	testIdentifier(t, exp.Property, "x")
+}
+
+func TestStructStatement(t *testing.T) {
+	input := `struct Point {
+	x, y,
+	fn norm(self) { self.x * self.x + self.y * self.y },
+	fn scale(self, k) { Point(self.x * k, self.y * k) }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+			len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.StructStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.StructStatement. got=%T", program.Statements[0])
+	}
+
+	testIdentifier(t, stmt.Name, "Point")
+	if len(stmt.Fields) != 2 {
+		t.Fatalf("wrong number of fields. want=2, got=%d", len(stmt.Fields))
+	}
+	testIdentifier(t, stmt.Fields[0], "x")
+	testIdentifier(t, stmt.Fields[1], "y")
+
+	if len(stmt.Methods) != 2 {
+		t.Fatalf("wrong number of methods. want=2, got=%d", len(stmt.Methods))
+	}
+	testIdentifier(t, stmt.Methods[0].Name, "norm")
+	testIdentifier(t, stmt.Methods[1].Name, "scale")
+	testIdentifier(t, stmt.Methods[1].Parameters[0], "self")
+}
+
+func TestStructStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct { x }", "expected next token to be IDENT, got { instead"},
+		{"struct P { x, x }", "duplicate member x in struct P"},
+		{"struct P { x, fn x(self) { 1 } }", "duplicate member x in struct P"},
+		{"struct P { fn(self) { 1 } }", "method in struct P must have a name"},
+		{"struct P { fn m() { 1 } }", "method m must take self as its first parameter"},
+		{"struct P { 1 }", "expected field or method in struct, got INT instead"},
+		{"struct P { x y }", "expected next token to be ,, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestEnumStatement(t *testing.T) {
+	input := "enum Shape { Circle(r), Rect(w, h), Empty }"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+			len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.EnumStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.EnumStatement. got=%T", program.Statements[0])
+	}
+
+	testIdentifier(t, stmt.Name, "Shape")
+
+	expected := []string{"Circle(r)", "Rect(w, h)", "Empty"}
+	if len(stmt.Variants) != len(expected) {
+		t.Fatalf("wrong number of variants. want=%d, got=%d", len(expected), len(stmt.Variants))
+	}
+	for i, want := range expected {
+		if stmt.Variants[i].String() != want {
+			t.Errorf("variants[%d] wrong. want=%q, got=%q", i, want, stmt.Variants[i].String())
+		}
+	}
+	if stmt.Variants[2].Fields != nil {
+		t.Errorf("unit variant has fields. got=%+v", stmt.Variants[2].Fields)
+	}
+}
+
+func TestEnumStatementErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enum { A }", "expected next token to be IDENT, got { instead"},
+		{"enum E { A, A }", "duplicate variant A in enum E"},
+		{"enum E { A(x, x) }", "duplicate field x in variant A"},
+		{"enum E { 1 }", "expected variant in enum, got INT instead"},
+		{"enum E { A(1) }", "expected next token to be IDENT, got INT instead"},
+		{"enum E { A B }", "expected next token to be ,, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
 func TestParsingEmptyHashLiteral(t *testing.T) {
 	input := "{}"
 
@@ -1112,3 +2049,224 @@ func TestMacroLiteralParsing(t *testing.T) {
 
 	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
 }
+
+func TestThrowStatement(t *testing.T) {
+	input := `throw "boom";`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ThrowStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if stmt.Value.String() != "boom" {
+		t.Errorf("stmt.Value wrong. got=%q", stmt.Value.String())
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input      string
+		catchParam string
+		hasCatch   bool
+		hasFinally bool
+	}{
+		{`try { x } catch (e) { y }`, "e", true, false},
+		{`try { x } catch { y }`, "", true, false},
+		{`try { x } finally { z }`, "", false, true},
+		{`try { x } catch (err) { y } finally { z }`, "err", true, true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.TryExpression. got=%T",
+				stmt.Expression)
+		}
+
+		if len(exp.Block.Statements) != 1 {
+			t.Errorf("try block is not 1 statements. got=%d", len(exp.Block.Statements))
+		}
+
+		if (exp.Catch != nil) != tt.hasCatch || (exp.Finally != nil) != tt.hasFinally {
+			t.Errorf("wrong clauses for %q. catch=%v, finally=%v",
+				tt.input, exp.Catch != nil, exp.Finally != nil)
+		}
+
+		if tt.catchParam == "" {
+			if exp.CatchParam != nil {
+				t.Errorf("exp.CatchParam is not nil. got=%q", exp.CatchParam.String())
+			}
+		} else {
+			testIdentifier(t, exp.CatchParam, tt.catchParam)
+		}
+	}
+}
+
+func TestTryExpressionErrors(t *testing.T) {
+	l := lexer.New(`try { x }`)
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "expected catch or finally after try block" {
+		t.Errorf("wrong parser errors. got=%q", errors)
+	}
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	input := `match x {
+	0 => "zero",
+	-1 => "minus one",
+	[a, [b], ...rest] if a > b => rest,
+	{"name": n, age, 1: true} => n,
+	Shape.Rect(w, 2) => w,
+	Color.Red => 1,
+	_ => fn(y) { y },
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MatchExpression. got=%T",
+			stmt.Expression)
+	}
+
+	if !testIdentifier(t, exp.Subject, "x") {
+		return
+	}
+
+	expected := []struct {
+		pattern string
+		guard   string
+		body    string
+	}{
+		{"0", "", "zero"},
+		{"(-1)", "", "minus one"},
+		{"[a, [b], ...rest]", "(a > b)", "rest"},
+		{"{name : n, age : age, 1 : true}", "", "n"},
+		{"Shape.Rect(w, 2)", "", "w"},
+		{"Color.Red", "", "1"},
+		{"_", "", "fn(y) {\n\ty;\n}"},
+	}
+
+	if len(exp.Arms) != len(expected) {
+		t.Fatalf("exp.Arms has wrong length. want=%d, got=%d", len(expected), len(exp.Arms))
+	}
+
+	for i, tt := range expected {
+		arm := exp.Arms[i]
+		if arm.Pattern.String() != tt.pattern {
+			t.Errorf("arms[%d] pattern wrong. want=%q, got=%q", i, tt.pattern, arm.Pattern.String())
+		}
+		guard := ""
+		if arm.Guard != nil {
+			guard = arm.Guard.String()
+		}
+		if guard != tt.guard {
+			t.Errorf("arms[%d] guard wrong. want=%q, got=%q", i, tt.guard, guard)
+		}
+		if arm.Body.String() != tt.body {
+			t.Errorf("arms[%d] body wrong. want=%q, got=%q", i, tt.body, arm.Body.String())
+		}
+	}
+
+	hashPattern, ok := exp.Arms[3].Pattern.(*ast.HashPattern)
+	if !ok {
+		t.Fatalf("arms[3] pattern is not ast.HashPattern. got=%T", exp.Arms[3].Pattern)
+	}
+	if _, ok := hashPattern.Keys[1].(*ast.StringLiteral); !ok {
+		t.Errorf("shorthand key is not ast.StringLiteral. got=%T", hashPattern.Keys[1])
+	}
+
+	unitPattern, ok := exp.Arms[5].Pattern.(*ast.VariantPattern)
+	if !ok {
+		t.Fatalf("arms[5] pattern is not ast.VariantPattern. got=%T", exp.Arms[5].Pattern)
+	}
+	if unitPattern.Fields != nil {
+		t.Errorf("unit variant pattern has fields. got=%+v", unitPattern.Fields)
+	}
+}
+
+func TestMatchAsIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match(re, s)`, "match(re, s);\n"},
+		{`re.match(s)`, "(re.match)(s);\n"},
+		{`match x { _ => match(re, x) }`, "match x {_ => match(re, x)};\n"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if actual := program.Statements[0].String(); actual != tt.expected {
+			t.Errorf("wrong program for %q. want=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match x { fn => 1 }`, "unexpected FUNCTION in pattern"},
+		{`match x { 1 2 }`, "expected next token to be =>, got INT instead"},
+		{`match x { [...a, b] => 1 }`, "expected next token to be ], got , instead"},
+		{`match x { {"a"} => 1 }`, "expected next token to be :, got } instead"},
+		{`match x { Shape.(r) => 1 }`, "expected next token to be IDENT, got ( instead"},
+		{`match x { Shape.Circle(r => 1 }`, "expected next token to be ,, got => instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong parser errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
@@ -39,6 +39,9 @@ func Start(in io.Reader, out io.Writer) {
 		if evaluated := evaluator.Eval(expended, env); evaluated != nil {
 			io.WriteString(out, evaluated.Inspect()+"\n")
 		}
+		if err := evaluator.RunEventLoop(); err != nil {
+			io.WriteString(out, err.Inspect()+"\n")
+		}
 	}
 }
 
@@ -25,10 +25,20 @@ const (
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	BAR      = "|"
+	PIPELINE = "|>"
+
+	NULLISH          = "??"
+	OPTIONAL_DOT     = "?."
+	OPTIONAL_BRACKET = "?["
+
 	// 分隔符
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	ARROW     = "=>"
+	ELLIPSIS  = "..."
+	DOT       = "."
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -46,22 +56,60 @@ const (
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
 	MACRO    = "MACRO"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	MATCH    = "MATCH"
+	FOR      = "FOR"
+	IN       = "IN"
+	YIELD    = "YIELD"
+	SELECT   = "SELECT"
+	CASE     = "CASE"
+	DEFAULT  = "DEFAULT"
+	SWITCH   = "SWITCH"
+	ASYNC    = "ASYNC"
+	AWAIT    = "AWAIT"
+	CONST    = "CONST"
+	STRUCT   = "STRUCT"
+	ENUM     = "ENUM"
+	NULL     = "NULL"
 )
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int // 所在行，从1开始
+	Column  int // 所在列，从1开始
 }
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"macro":  MACRO,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"macro":   MACRO,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
+	"match":   MATCH,
+	"for":     FOR,
+	"in":      IN,
+	"yield":   YIELD,
+	"select":  SELECT,
+	"case":    CASE,
+	"default": DEFAULT,
+	"switch":  SWITCH,
+	"async":   ASYNC,
+	"await":   AWAIT,
+	"const":   CONST,
+	"struct":  STRUCT,
+	"enum":    ENUM,
+	"null":    NULL,
 }
 
 func LookupIdent(ident string) TokenType {